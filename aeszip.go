@@ -0,0 +1,347 @@
+package epub
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+)
+
+// Constants of the WinZip AES encryption format
+const (
+	// methodWinZipAES is the compression method of AES encrypted entries,
+	// whose actual method is stored in the AES extra field
+	methodWinZipAES = 99
+
+	// aesExtraID is the header ID of the AES extra field
+	aesExtraID = 0x9901
+
+	// aesIterations is the number of PBKDF2 iterations used to derive the
+	// keys from the password
+	aesIterations = 1000
+
+	// aesAuthCodeSize is the size of the HMAC-SHA1 authentication code that
+	// follows the encrypted data
+	aesAuthCodeSize = 10
+)
+
+// OpenEncryptedZip opens and parses an EPUB wrapped in a password-protected
+// zip archive
+//
+// Some distributors encrypt the zip archive of an EPUB with WinZip AES
+// encryption, which archive/zip cannot read. Entries encrypted with AE-1 or
+// AE-2 at any key strength are decrypted with password, while unencrypted
+// entries are read as usual. This is unrelated to DRM, which encrypts the
+// content inside the archive and is reported with ErrDRMProtected.
+//
+// ErrWrongPassword is returned if password does not decrypt the archive,
+// and ErrNotEncrypted if no entry of the archive is AES encrypted; use Open
+// for such files. Entries protected with the legacy ZipCrypto scheme cannot
+// be read.
+//
+// Example:
+//
+//	e, err := epub.OpenEncryptedZip("book.zip", "secret")
+//	if errors.Is(err, epub.ErrWrongPassword) {
+//		log.Fatal("wrong password")
+//	}
+//	defer e.Close()
+func OpenEncryptedZip(path, password string, opts ...Option) (*Epub, error) {
+	options := applyOptions(opts...)
+	if err := options.checkContext(); err != nil {
+		return nil, err
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, zipError(err)
+	}
+
+	src, err := newAESZipSource(&reader.Reader, password)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	epub := newEpub(src, opts)
+	epub.File = &reader.Reader
+	epub.readCloser = reader
+
+	if err := epub.parse(options.ctx); err != nil {
+		if errors.Is(err, ErrDRMProtected) {
+			return epub, err
+		}
+		epub.Close()
+		return nil, err
+	}
+
+	return epub, nil
+}
+
+// aesZipSource reads the files of an EPUB from a zip archive whose entries
+// may be encrypted with WinZip AES encryption
+type aesZipSource struct {
+	*zipSource
+	password []byte
+}
+
+// newAESZipSource returns a source reading from the zip archive r, which
+// must contain at least one AES encrypted entry, decrypting with password
+//
+// The password is checked against the first encrypted entry, so that a
+// wrong password is reported before anything is parsed.
+func newAESZipSource(r *zip.Reader, password string) (*aesZipSource, error) {
+	s := &aesZipSource{zipSource: newZipSource(r), password: []byte(password)}
+
+	for _, name := range s.names {
+		file := s.files[name]
+		params, ok, err := parseAESExtra(file)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		raw, err := file.OpenRaw()
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := s.deriveKeys(file, params, raw); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	return nil, ErrNotEncrypted
+}
+
+func (s *aesZipSource) open(name string) (io.ReadCloser, error) {
+	file, ok := s.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	params, ok, err := parseAESExtra(file)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if file.Flags&0x1 != 0 {
+			return nil, fmt.Errorf("%s: unsupported zip encryption", name)
+		}
+		return file.Open()
+	}
+
+	raw, err := file.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+	stream, mac, err := s.deriveKeys(file, params, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	overhead := uint64(params.saltSize() + 2 + aesAuthCodeSize)
+	if file.CompressedSize64 < overhead {
+		return nil, fmt.Errorf("%w: %s: truncated encrypted entry", zip.ErrFormat, name)
+	}
+	var r io.Reader = &aesReader{
+		name:   name,
+		data:   io.LimitReader(raw, int64(file.CompressedSize64-overhead)),
+		raw:    raw,
+		stream: stream,
+		mac:    mac,
+	}
+
+	var closer io.Closer = io.NopCloser(nil)
+	switch params.method {
+	case zip.Store:
+	case zip.Deflate:
+		fr := flate.NewReader(r)
+		r, closer = fr, fr
+	default:
+		return nil, fmt.Errorf("%w: %s", zip.ErrAlgorithm, name)
+	}
+
+	// AE-2 leaves the CRC empty, relying on the authentication code alone
+	if params.version == 1 {
+		r = &crcReader{name: name, r: r, hash: crc32.NewIEEE(), want: file.CRC32}
+	}
+	return readCloser{Reader: r, Closer: closer}, nil
+}
+
+func (s *aesZipSource) method(name string) (uint16, bool) {
+	file, ok := s.files[name]
+	if !ok {
+		return 0, false
+	}
+	if params, ok, err := parseAESExtra(file); ok && err == nil {
+		return params.method, true
+	}
+	return file.Method, true
+}
+
+// deriveKeys reads the salt and password verification value at the start
+// of the raw data of an encrypted entry and returns the decryption stream
+// and authentication hash for the rest of the data
+func (s *aesZipSource) deriveKeys(file *zip.File, params aesParams, raw io.Reader) (cipher.Stream, hash.Hash, error) {
+	header := make([]byte, params.saltSize()+2)
+	if _, err := io.ReadFull(raw, header); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	salt, verifier := header[:len(header)-2], header[len(header)-2:]
+
+	keySize := params.keySize()
+	keys, err := pbkdf2.Key(sha1.New, string(s.password), salt, aesIterations, 2*keySize+2)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hmac.Equal(keys[2*keySize:], verifier) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrWrongPassword, file.Name)
+	}
+
+	block, err := aes.NewCipher(keys[:keySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	return newWinZipCTR(block), hmac.New(sha1.New, keys[keySize:2*keySize]), nil
+}
+
+// aesParams holds the contents of the AES extra field of an entry
+type aesParams struct {
+	// version is 1 for AE-1 and 2 for AE-2
+	version uint16
+
+	// strength is 1, 2 or 3 for 128, 192 or 256 bit keys
+	strength byte
+
+	// method is the compression method of the decrypted data
+	method uint16
+}
+
+func (p aesParams) keySize() int {
+	return 8 + 8*int(p.strength)
+}
+
+func (p aesParams) saltSize() int {
+	return 4 + 4*int(p.strength)
+}
+
+// parseAESExtra reads the AES extra field of an entry
+//
+// ok is false if the entry is not AES encrypted.
+func parseAESExtra(file *zip.File) (params aesParams, ok bool, err error) {
+	if file.Method != methodWinZipAES {
+		return aesParams{}, false, nil
+	}
+
+	extra := file.Extra
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			break
+		}
+		if id == aesExtraID && size >= 7 {
+			params = aesParams{
+				version:  binary.LittleEndian.Uint16(extra),
+				strength: extra[4],
+				method:   binary.LittleEndian.Uint16(extra[5:]),
+			}
+			if params.strength < 1 || params.strength > 3 || string(extra[2:4]) != "AE" {
+				break
+			}
+			return params, true, nil
+		}
+		extra = extra[size:]
+	}
+
+	return aesParams{}, false, fmt.Errorf("%w: %s: invalid AES extra field", zip.ErrFormat, file.Name)
+}
+
+// winZipCTR is the counter mode used by WinZip AES encryption, which unlike
+// cipher.NewCTR increments its counter as a little-endian number starting
+// at 1
+type winZipCTR struct {
+	block   cipher.Block
+	counter [aes.BlockSize]byte
+	stream  [aes.BlockSize]byte
+	used    int
+}
+
+func newWinZipCTR(block cipher.Block) *winZipCTR {
+	return &winZipCTR{block: block, used: aes.BlockSize}
+}
+
+func (c *winZipCTR) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if c.used == aes.BlockSize {
+			for j := range c.counter {
+				c.counter[j]++
+				if c.counter[j] != 0 {
+					break
+				}
+			}
+			c.block.Encrypt(c.stream[:], c.counter[:])
+			c.used = 0
+		}
+		dst[i] = src[i] ^ c.stream[c.used]
+		c.used++
+	}
+}
+
+// aesReader decrypts the data of an AES encrypted entry, checking its
+// authentication code once all data has been read
+type aesReader struct {
+	name   string
+	data   io.Reader
+	raw    io.Reader
+	stream cipher.Stream
+	mac    hash.Hash
+}
+
+func (r *aesReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	r.mac.Write(p[:n])
+	r.stream.XORKeyStream(p[:n], p[:n])
+
+	if err == io.EOF {
+		code := make([]byte, aesAuthCodeSize)
+		if _, err := io.ReadFull(r.raw, code); err != nil {
+			return n, fmt.Errorf("failed to read %s: %w", r.name, err)
+		}
+		if !hmac.Equal(r.mac.Sum(nil)[:aesAuthCodeSize], code) {
+			return n, fmt.Errorf("%w: %s", zip.ErrChecksum, r.name)
+		}
+	}
+	return n, err
+}
+
+// crcReader checks the CRC-32 of the data read from r once it is exhausted
+type crcReader struct {
+	name string
+	r    io.Reader
+	hash hash.Hash32
+	want uint32
+}
+
+func (r *crcReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF && r.hash.Sum32() != r.want {
+		return n, fmt.Errorf("%w: %s", zip.ErrChecksum, r.name)
+	}
+	return n, err
+}
@@ -0,0 +1,180 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildTestAESZip builds an EPUB whose entries, except the mimetype, are
+// deflated and encrypted with 256 bit WinZip AES encryption of the given
+// version, and writes it to a temporary file
+func buildTestAESZip(t *testing.T, files map[string]string, password string, version uint16) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	mw, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatalf("Failed to create mimetype entry: %v", err)
+	}
+	mw.Write([]byte("application/epub+zip"))
+
+	if _, ok := files["META-INF/container.xml"]; !ok {
+		files["META-INF/container.xml"] = testContainerXML
+	}
+
+	for name, content := range files {
+		var compressed bytes.Buffer
+		fw, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+		fw.Write([]byte(content))
+		fw.Close()
+
+		salt := bytes.Repeat([]byte{byte(len(name))}, 16)
+		keys, err := pbkdf2.Key(sha1.New, password, salt, 1000, 2*32+2)
+		if err != nil {
+			t.Fatalf("Failed to derive keys: %v", err)
+		}
+		block, err := aes.NewCipher(keys[:32])
+		if err != nil {
+			t.Fatalf("Failed to create cipher: %v", err)
+		}
+		encrypted := compressed.Bytes()
+		newWinZipCTR(block).XORKeyStream(encrypted, encrypted)
+		mac := hmac.New(sha1.New, keys[32:64])
+		mac.Write(encrypted)
+
+		var data bytes.Buffer
+		data.Write(salt)
+		data.Write(keys[64:])
+		data.Write(encrypted)
+		data.Write(mac.Sum(nil)[:10])
+
+		extra := make([]byte, 11)
+		binary.LittleEndian.PutUint16(extra, aesExtraID)
+		binary.LittleEndian.PutUint16(extra[2:], 7)
+		binary.LittleEndian.PutUint16(extra[4:], version)
+		copy(extra[6:], "AE")
+		extra[8] = 3
+		binary.LittleEndian.PutUint16(extra[9:], zip.Deflate)
+
+		header := &zip.FileHeader{
+			Name:               name,
+			Method:             methodWinZipAES,
+			Flags:              0x1,
+			Extra:              extra,
+			CompressedSize64:   uint64(data.Len()),
+			UncompressedSize64: uint64(len(content)),
+		}
+		if version == 1 {
+			header.CRC32 = crc32.ChecksumIEEE([]byte(content))
+		}
+		rw, err := w.CreateRaw(header)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry %s: %v", name, err)
+		}
+		rw.Write(data.Bytes())
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip writer: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "book.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+	return path
+}
+
+func TestOpenEncryptedZip(t *testing.T) {
+	for _, version := range []uint16{1, 2} {
+		path := buildTestAESZip(t, testEPUB3Files(), "secret", version)
+
+		epub, err := OpenEncryptedZip(path, "secret")
+		if err != nil {
+			t.Fatalf("Failed to open AE-%d encrypted EPUB: %v", version, err)
+		}
+
+		if epub.GetTitle() != "Nav Test" {
+			t.Errorf("Expected title 'Nav Test', got %q", epub.GetTitle())
+		}
+		content, err := epub.GetChapterContent(0)
+		if err != nil {
+			t.Fatalf("Failed to get chapter content: %v", err)
+		}
+		if content != testChapterXHTML {
+			t.Errorf("Unexpected chapter content: %q", content)
+		}
+		if err := epub.CheckMimetype(); err != nil {
+			t.Errorf("Expected the unencrypted mimetype to be valid, got %v", err)
+		}
+		epub.Close()
+
+		if _, err := OpenEncryptedZip(path, "wrong"); !errors.Is(err, ErrWrongPassword) {
+			t.Errorf("Expected ErrWrongPassword, got %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "plain.epub")
+	if err := os.WriteFile(path, buildTestZip(t, testEPUB3Files()), 0o644); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+	if _, err := OpenEncryptedZip(path, "secret"); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Expected ErrNotEncrypted, got %v", err)
+	}
+}
+
+func TestAESZipSource_Tampered(t *testing.T) {
+	path := buildTestAESZip(t, testEPUB3Files(), "secret", 2)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+
+	for _, file := range mustZipReader(t, data).File {
+		if file.Name == "OEBPS/text/one.xhtml" {
+			offset, err := file.DataOffset()
+			if err != nil {
+				t.Fatalf("Failed to get data offset: %v", err)
+			}
+			// Flip a bit of the encrypted data after the salt and verifier
+			data[offset+20] ^= 0x01
+		}
+	}
+
+	src, err := newAESZipSource(mustZipReader(t, data), "secret")
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	rc, err := src.open("OEBPS/text/one.xhtml")
+	if err != nil {
+		t.Fatalf("Failed to open entry: %v", err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); err == nil {
+		t.Error("Expected tampered data to be rejected")
+	}
+}
+
+func mustZipReader(t *testing.T, data []byte) *zip.Reader {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+	return r
+}
@@ -0,0 +1,89 @@
+package epub
+
+import (
+	"container/list"
+	"sync"
+)
+
+// fileCache is a size-bounded LRU cache of decompressed zip entries
+//
+// The cache is safe for concurrent use. It stores and returns copies of the
+// cached data, so callers are free to modify the slices they receive.
+type fileCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// cacheEntry is an element of a fileCache's LRU list
+type cacheEntry struct {
+	name string
+	data []byte
+}
+
+// newFileCache returns a cache holding up to maxBytes of file data, or nil if
+// maxBytes is not positive
+func newFileCache(maxBytes int64) *fileCache {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &fileCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the cached content of the named entry
+func (c *fileCache) get(name string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[name]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return append([]byte(nil), elem.Value.(*cacheEntry).data...), true
+}
+
+// add stores a copy of the content of the named entry, evicting the least
+// recently used entries until the cache fits its budget. Content larger than
+// the whole budget is not cached.
+func (c *fileCache) add(name string, data []byte) {
+	size := int64(len(data))
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[name] = c.order.PushFront(&cacheEntry{name: name, data: append([]byte(nil), data...)})
+	c.size += size
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.name)
+		c.size -= int64(len(entry.data))
+	}
+}
+
+// clear removes all entries from the cache
+func (c *fileCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+	c.size = 0
+}
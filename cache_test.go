@@ -0,0 +1,73 @@
+package epub
+
+import (
+	"testing"
+)
+
+func TestFileCache_Eviction(t *testing.T) {
+	cache := newFileCache(10)
+
+	cache.add("a", []byte("aaaa"))
+	cache.add("b", []byte("bbbb"))
+
+	// Touch a so that b becomes the least recently used entry
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+
+	cache.add("c", []byte("cccc"))
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	for _, name := range []string{"a", "c"} {
+		if _, ok := cache.get(name); !ok {
+			t.Errorf("Expected %s to be cached", name)
+		}
+	}
+
+	cache.add("big", make([]byte, 11))
+	if _, ok := cache.get("big"); ok {
+		t.Error("Expected entry larger than the budget not to be cached")
+	}
+
+	data, _ := cache.get("a")
+	data[0] = 'x'
+	if again, _ := cache.get("a"); string(again) != "aaaa" {
+		t.Errorf("Expected cached data to be unaffected by callers, got %q", again)
+	}
+
+	if newFileCache(0) != nil {
+		t.Error("Expected a zero budget to disable the cache")
+	}
+}
+
+func TestEpub_WithCacheSize(t *testing.T) {
+	data := buildTestZip(t, testEPUB3Files())
+	epub, err := OpenBytes(data, WithCacheSize(1<<20))
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if epub.cache == nil {
+		t.Fatal("Expected cache to be enabled")
+	}
+
+	first, err := epub.GetChapterContent(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+	if _, ok := epub.cache.get("OEBPS/text/one.xhtml"); !ok {
+		t.Error("Expected chapter to be cached after reading it")
+	}
+
+	second, err := epub.GetChapterContent(0)
+	if err != nil || second != first {
+		t.Errorf("Expected cached chapter content to match, got %q (%v)", second, err)
+	}
+
+	if newTestEpub(t, testEPUB3Files()).cache != nil {
+		t.Error("Expected caching to be disabled by default")
+	}
+}
@@ -0,0 +1,79 @@
+package epub
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// xmlEncodingPattern and metaCharsetPattern match the encoding declared by
+// an XML declaration, an HTML5 <meta charset> element and an HTML4
+// http-equiv <meta> element; charsetValuePattern matches the declared value
+// for rewriting
+var (
+	xmlEncodingPattern  = regexp.MustCompile(`^(?:\xef\xbb\xbf)?\s*<\?xml[^>]*?\sencoding\s*=\s*["']([^"']+)["']`)
+	metaCharsetPattern  = regexp.MustCompile(`(?i)<meta\s[^>]*?charset\s*=\s*["']?([\w.:-]+)`)
+	charsetValuePattern = regexp.MustCompile(`(?i)(encoding\s*=\s*["']|charset\s*=\s*["']?)[\w.:-]+`)
+)
+
+// charsetSniffLength is the number of leading bytes searched for a charset
+// declaration
+const charsetSniffLength = 1024
+
+// declaredCharset returns the character encoding declared at the start of an
+// (X)HTML document, or an empty string if there is none
+//
+// The XML declaration takes precedence over a <meta> charset, as it does for
+// XML parsers.
+func declaredCharset(content []byte) string {
+	head := content[:min(len(content), charsetSniffLength)]
+
+	if m := xmlEncodingPattern.FindSubmatch(head); m != nil {
+		return string(m[1])
+	}
+	if m := metaCharsetPattern.FindSubmatch(head); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// toUTF8 transcodes an (X)HTML document to UTF-8 from the encoding it
+// declares
+//
+// Documents declaring no encoding or UTF-8 are returned unchanged. The
+// encoding declarations of converted documents are rewritten to UTF-8, so the
+// result can be parsed again without being decoded twice.
+func toUTF8(content []byte) ([]byte, error) {
+	charset := declaredCharset(content)
+	if charset == "" {
+		return content, nil
+	}
+
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	if name, _ := htmlindex.Name(enc); name == "utf-8" {
+		return content, nil
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s content: %w", charset, err)
+	}
+
+	// Rewrite the declarations, which are plain ASCII in every supported
+	// encoding and therefore survive decoding unchanged. Decoding can grow
+	// the content, so a larger prefix than was sniffed is searched.
+	n := min(len(decoded), 2*charsetSniffLength)
+	head := charsetValuePattern.ReplaceAllStringFunc(string(decoded[:n]), func(decl string) string {
+		m := charsetValuePattern.FindStringSubmatch(decl)
+		if !strings.EqualFold(decl[len(m[1]):], charset) {
+			return decl
+		}
+		return m[1] + "UTF-8"
+	})
+	return append([]byte(head), decoded[n:]...), nil
+}
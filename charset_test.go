@@ -0,0 +1,77 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestEpub_WithForceUTF8(t *testing.T) {
+	gbk, err := simplifiedchinese.GBK.NewEncoder().String(`<?xml version="1.0" encoding="GBK"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>第一章</title></head><body><p>你好，世界</p></body></html>`)
+	if err != nil {
+		t.Fatalf("Failed to encode GBK fixture: %v", err)
+	}
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(`<html><head><meta charset="Shift_JIS"/></head><body><p>こんにちは</p></body></html>`)
+	if err != nil {
+		t.Fatalf("Failed to encode Shift_JIS fixture: %v", err)
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = gbk
+	files["OEBPS/text/two.xhtml"] = sjis
+	epub := newTestEpub(t, files)
+
+	raw, err := epub.GetChapterContent(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+	if raw != gbk {
+		t.Error("Expected content to be returned unchanged by default")
+	}
+
+	content, err := epub.GetChapterContent(0, WithForceUTF8())
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+	if !strings.Contains(content, "<p>你好，世界</p>") || !strings.Contains(content, `encoding="UTF-8"`) {
+		t.Errorf("Expected GBK content transcoded to UTF-8, got %q", content)
+	}
+
+	text, err := epub.GetChapterText(1, WithForceUTF8())
+	if err != nil {
+		t.Fatalf("Failed to get chapter text: %v", err)
+	}
+	if text != "こんにちは" {
+		t.Errorf("Expected Shift_JIS text transcoded to UTF-8, got %q", text)
+	}
+
+	chapters, err := epub.GetChapters(WithForceUTF8())
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if len(chapters) != 2 || !strings.Contains(chapters[1].Content, `<meta charset="UTF-8"/>`) {
+		t.Errorf("Expected transcoded chapters, got %+v", chapters)
+	}
+}
+
+func TestToUTF8_Unchanged(t *testing.T) {
+	for _, content := range []string{
+		`<html><body><p>Plain</p></body></html>`,
+		`<?xml version="1.0" encoding="utf-8"?><html/>`,
+	} {
+		got, err := toUTF8([]byte(content))
+		if err != nil {
+			t.Fatalf("Failed to convert %q: %v", content, err)
+		}
+		if string(got) != content {
+			t.Errorf("Expected %q unchanged, got %q", content, got)
+		}
+	}
+
+	if _, err := toUTF8([]byte(`<?xml version="1.0" encoding="x-unknown"?><html/>`)); err == nil {
+		t.Error("Expected error for unsupported charset")
+	}
+}
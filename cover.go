@@ -0,0 +1,204 @@
+package epub
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF decoder for cover images
+	_ "image/jpeg" // register JPEG decoder for cover images
+	_ "image/png"  // register PNG decoder for cover images
+)
+
+// GetCoverImage returns the decoded cover image of the EPUB
+//
+// This method locates the cover in the same way as GetCover and decodes it.
+// The returned format is the name of the image format, such as "jpeg", "png"
+// or "gif". If the EPUB has no cover image, ErrNoCover is returned.
+//
+// Example:
+//
+//	img, format, err := e.GetCoverImage()
+//	if errors.Is(err, epub.ErrNoCover) {
+//		fmt.Println("No cover image found")
+//	} else if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(format, img.Bounds())
+func (e *Epub) GetCoverImage() (image.Image, string, error) {
+	cover, err := e.GetCover()
+	if err != nil {
+		return nil, "", err
+	}
+	if cover == nil {
+		return nil, "", ErrNoCover
+	}
+	defer cover.Close()
+
+	img, format, err := image.Decode(cover)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode cover image: %w", err)
+	}
+	return img, format, nil
+}
+
+// CoverInfo describes the cover image of the EPUB
+type CoverInfo struct {
+	// MediaType is the media type of the cover, such as "image/jpeg"
+	MediaType string
+
+	// Width and Height are the pixel dimensions of the cover, or zero if
+	// its format cannot be decoded
+	Width  int
+	Height int
+
+	// SizeBytes is the size of the cover file
+	SizeBytes int64
+}
+
+// GetCoverInfo returns the media type, dimensions and size of the cover
+// image
+//
+// Only the header of the image is read to determine its dimensions, which
+// makes this much cheaper than GetCoverImage, e.g. for listing views. The
+// media type is taken from the manifest. For covers in formats without a
+// registered decoder, such as SVG, Width and Height are zero. If the EPUB
+// has no cover image, ErrNoCover is returned.
+//
+// Example:
+//
+//	info, err := e.GetCoverInfo()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%s, %dx%d, %d bytes\n", info.MediaType, info.Width, info.Height, info.SizeBytes)
+func (e *Epub) GetCoverInfo() (CoverInfo, error) {
+	item := e.findCoverItem()
+	if item == nil {
+		return CoverInfo{}, ErrNoCover
+	}
+
+	size, err := e.itemSize(item)
+	if err != nil {
+		return CoverInfo{}, err
+	}
+	info := CoverInfo{MediaType: itemMediaType(item), SizeBytes: size}
+
+	cover, err := e.GetFileReader(e.resolveHref(item.Href))
+	if err != nil {
+		return CoverInfo{}, err
+	}
+	defer cover.Close()
+
+	config, _, err := image.DecodeConfig(cover)
+	if errors.Is(err, image.ErrFormat) {
+		return info, nil
+	}
+	if err != nil {
+		return CoverInfo{}, fmt.Errorf("failed to decode cover image: %w", err)
+	}
+	info.Width, info.Height = config.Width, config.Height
+	return info, nil
+}
+
+// GetCoverThumbnail returns the cover image scaled to fit within the bounds
+//
+// The cover is scaled with bilinear interpolation so that it fits within
+// maxWidth x maxHeight while preserving its aspect ratio. Covers that already
+// fit are returned unscaled. If the EPUB has no cover image, ErrNoCover is
+// returned.
+//
+// Example:
+//
+//	thumb, err := e.GetCoverThumbnail(200, 300)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	png.Encode(out, thumb)
+func (e *Epub) GetCoverThumbnail(maxWidth, maxHeight int) (image.Image, error) {
+	if maxWidth <= 0 || maxHeight <= 0 {
+		return nil, fmt.Errorf("invalid thumbnail size %dx%d", maxWidth, maxHeight)
+	}
+
+	img, _, err := e.GetCoverImage()
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxWidth && height <= maxHeight {
+		return img, nil
+	}
+
+	// Scale by the more constraining dimension to preserve the aspect ratio
+	scale := min(float64(maxWidth)/float64(width), float64(maxHeight)/float64(height))
+	dstWidth := max(1, int(float64(width)*scale))
+	dstHeight := max(1, int(float64(height)*scale))
+
+	return resizeBilinear(img, dstWidth, dstHeight), nil
+}
+
+// resizeBilinear scales src to the given size using bilinear interpolation
+func resizeBilinear(src image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	bounds := src.Bounds()
+
+	xRatio := float64(bounds.Dx()) / float64(width)
+	yRatio := float64(bounds.Dy()) / float64(height)
+
+	for y := 0; y < height; y++ {
+		// Map the center of the destination pixel into source coordinates
+		sy := (float64(y)+0.5)*yRatio - 0.5
+		y0 := clamp(int(sy), 0, bounds.Dy()-1)
+		y1 := clamp(y0+1, 0, bounds.Dy()-1)
+		fy := clampFloat(sy - float64(y0))
+
+		for x := 0; x < width; x++ {
+			sx := (float64(x)+0.5)*xRatio - 0.5
+			x0 := clamp(int(sx), 0, bounds.Dx()-1)
+			x1 := clamp(x0+1, 0, bounds.Dx()-1)
+			fx := clampFloat(sx - float64(x0))
+
+			c00 := src.At(bounds.Min.X+x0, bounds.Min.Y+y0)
+			c10 := src.At(bounds.Min.X+x1, bounds.Min.Y+y0)
+			c01 := src.At(bounds.Min.X+x0, bounds.Min.Y+y1)
+			c11 := src.At(bounds.Min.X+x1, bounds.Min.Y+y1)
+
+			dst.SetRGBA64(x, y, lerpColor(c00, c10, c01, c11, fx, fy))
+		}
+	}
+
+	return dst
+}
+
+// lerpColor bilinearly interpolates between four neighbouring colors
+func lerpColor(c00, c10, c01, c11 color.Color, fx, fy float64) color.RGBA64 {
+	r00, g00, b00, a00 := c00.RGBA()
+	r10, g10, b10, a10 := c10.RGBA()
+	r01, g01, b01, a01 := c01.RGBA()
+	r11, g11, b11, a11 := c11.RGBA()
+
+	lerp := func(v00, v10, v01, v11 uint32) uint16 {
+		top := float64(v00)*(1-fx) + float64(v10)*fx
+		bottom := float64(v01)*(1-fx) + float64(v11)*fx
+		return uint16(top*(1-fy) + bottom*fy)
+	}
+
+	return color.RGBA64{
+		R: lerp(r00, r10, r01, r11),
+		G: lerp(g00, g10, g01, g11),
+		B: lerp(b00, b10, b01, b11),
+		A: lerp(a00, a10, a01, a11),
+	}
+}
+
+// clamp limits v to the range [lo, hi]
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// clampFloat limits v to the range [0, 1]
+func clampFloat(v float64) float64 {
+	return max(0, min(v, 1))
+}
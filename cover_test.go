@@ -0,0 +1,164 @@
+package epub
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testCoverFiles returns an EPUB 3 book whose manifest declares the given
+// cover item (placed under OEBPS/images/) with the given image content
+func testCoverFiles(t *testing.T, item string, width, height int) map[string]string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode test cover: %v", err)
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, "<manifest>", "<manifest>\n\t\t"+item, 1)
+	files["OEBPS/images/cover.png"] = buf.String()
+	return files
+}
+
+func TestEpub_GetCoverImage(t *testing.T) {
+	files := testCoverFiles(t, `<item id="cover" href="images/cover.png" media-type="image/png"/>`, 40, 80)
+	epub := newTestEpub(t, files)
+
+	img, format, err := epub.GetCoverImage()
+	if err != nil {
+		t.Fatalf("Failed to get cover image: %v", err)
+	}
+
+	if format != "png" {
+		t.Errorf("Expected png format, got %q", format)
+	}
+
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 80 {
+		t.Errorf("Unexpected cover size %v", img.Bounds())
+	}
+
+	thumb, err := epub.GetCoverThumbnail(10, 10)
+	if err != nil {
+		t.Fatalf("Failed to get cover thumbnail: %v", err)
+	}
+
+	if thumb.Bounds().Dx() != 5 || thumb.Bounds().Dy() != 10 {
+		t.Errorf("Expected 5x10 thumbnail, got %v", thumb.Bounds())
+	}
+
+	r, g, b, _ := thumb.At(2, 5).RGBA()
+	if r>>8 != 200 || g>>8 != 100 || b>>8 != 50 {
+		t.Errorf("Unexpected thumbnail color %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestEpub_GetCoverImage_NoCover(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	if _, _, err := epub.GetCoverImage(); !errors.Is(err, ErrNoCover) {
+		t.Errorf("Expected ErrNoCover, got %v", err)
+	}
+
+	if _, err := epub.GetCoverThumbnail(100, 100); !errors.Is(err, ErrNoCover) {
+		t.Errorf("Expected ErrNoCover from thumbnail, got %v", err)
+	}
+}
+
+func TestEpub_GetCoverInfo(t *testing.T) {
+	files := testCoverFiles(t, `<item id="cover" href="images/cover.png" media-type="image/png"/>`, 40, 80)
+	epub := newTestEpub(t, files)
+
+	info, err := epub.GetCoverInfo()
+	if err != nil {
+		t.Fatalf("Failed to get cover info: %v", err)
+	}
+	want := CoverInfo{MediaType: "image/png", Width: 40, Height: 80, SizeBytes: int64(len(files["OEBPS/images/cover.png"]))}
+	if info != want {
+		t.Errorf("Expected %+v, got %+v", want, info)
+	}
+
+	files = testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, "<manifest>",
+		`<manifest><item id="cover" href="images/cover.svg" media-type="image/svg+xml" properties="cover-image"/>`, 1)
+	files["OEBPS/images/cover.svg"] = `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="20"/>`
+	info, err = newTestEpub(t, files).GetCoverInfo()
+	if err != nil {
+		t.Fatalf("Failed to get SVG cover info: %v", err)
+	}
+	if info.MediaType != "image/svg+xml" || info.Width != 0 || info.Height != 0 || info.SizeBytes == 0 {
+		t.Errorf("Unexpected SVG cover info %+v", info)
+	}
+
+	if _, err := newTestEpub(t, testEPUB3Files()).GetCoverInfo(); !errors.Is(err, ErrNoCover) {
+		t.Errorf("Expected ErrNoCover, got %v", err)
+	}
+}
+
+func TestEpub_GetCover_MetaTag(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	cover, err := epub.GetCover()
+	if err != nil {
+		t.Fatalf("Failed to get cover: %v", err)
+	}
+	if cover == nil {
+		t.Fatal("Expected cover referenced by <meta name=\"cover\">, got nil")
+	}
+	cover.Close()
+
+	_, format, err := epub.GetCoverImage()
+	if err != nil {
+		t.Fatalf("Failed to decode cover: %v", err)
+	}
+	if format != "jpeg" {
+		t.Errorf("Expected jpeg cover, got %q", format)
+	}
+}
+
+func TestEpub_GetCover_Properties(t *testing.T) {
+	files := testCoverFiles(t, `<item id="img" href="images/cover.png" media-type="image/png" properties="cover-image"/>`, 4, 4)
+	epub := newTestEpub(t, files)
+
+	cover, err := epub.GetCover()
+	if err != nil {
+		t.Fatalf("Failed to get cover: %v", err)
+	}
+	if cover == nil {
+		t.Fatal("Expected cover identified by properties=\"cover-image\", got nil")
+	}
+	cover.Close()
+}
+
+func TestEpub_GetCoverPath(t *testing.T) {
+	files := testCoverFiles(t, `<item id="img" href="images/cover.png" media-type="image/png" properties="cover-image"/>`, 10, 10)
+	epub := newTestEpub(t, files)
+
+	coverPath, err := epub.GetCoverPath()
+	if err != nil {
+		t.Fatalf("Failed to get cover path: %v", err)
+	}
+	if coverPath != "OEBPS/images/cover.png" {
+		t.Errorf("Expected cover path OEBPS/images/cover.png, got %q", coverPath)
+	}
+
+	if _, err := newTestEpub(t, testEPUB3Files()).GetCoverPath(); !errors.Is(err, ErrNoCover) {
+		t.Errorf("Expected ErrNoCover, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package epub
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/net/html"
+)
+
+// GetChapterDOM returns the parsed node tree of a specific chapter
+//
+// The chapter is parsed with the HTML5 parsing algorithm of
+// golang.org/x/net/html, so that callers can run their own queries and
+// transformations without parsing the content again. Chapters declaring a
+// legacy encoding are decoded to UTF-8 first, as with WithForceUTF8. The
+// returned tree is the document node; it is built anew on every call, is
+// not cached, and belongs to the caller, who may modify it freely. The index
+// is zero-based, as for GetChapterContent.
+//
+// Example:
+//
+//	doc, err := e.GetChapterDOM(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	var walk func(n *html.Node)
+//	walk = func(n *html.Node) {
+//		if n.Type == html.ElementNode && n.Data == "img" {
+//			// ...
+//		}
+//		for c := n.FirstChild; c != nil; c = c.NextSibling {
+//			walk(c)
+//		}
+//	}
+//	walk(doc)
+func (e *Epub) GetChapterDOM(chapterIndex int) (*html.Node, error) {
+	content, err := e.GetChapterContentBytes(chapterIndex, WithForceUTF8())
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chapter: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,48 @@
+package epub
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestEpub_GetChapterDOM(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	doc, err := epub.GetChapterDOM(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter DOM: %v", err)
+	}
+	if doc.Type != html.DocumentNode {
+		t.Fatalf("Expected a document node, got type %d", doc.Type)
+	}
+
+	var text string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "p" && n.FirstChild != nil {
+			text = n.FirstChild.Data
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	if text != "Text" {
+		t.Errorf("Expected paragraph text %q, got %q", "Text", text)
+	}
+
+	// Every call returns a new tree
+	other, err := epub.GetChapterDOM(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter DOM: %v", err)
+	}
+	if other == doc {
+		t.Error("Expected a new tree on every call")
+	}
+
+	if _, err := epub.GetChapterDOM(10); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package epub
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Font obfuscation algorithms. Resources encrypted with these algorithms are
+// only obfuscated and do not indicate DRM.
+const (
+	algorithmIDPFFont  = "http://www.idpf.org/2008/embedding"
+	algorithmAdobeFont = "http://ns.adobe.com/pdf/enc#RC"
+)
+
+// encryptionXML represents the META-INF/encryption.xml file structure
+type encryptionXML struct {
+	EncryptedData []encryptedData `xml:"EncryptedData"`
+}
+
+// encryptedData represents a single encrypted resource
+type encryptedData struct {
+	Method struct {
+		Algorithm string `xml:"Algorithm,attr"`
+	} `xml:"EncryptionMethod"`
+	CipherReference struct {
+		URI string `xml:"URI,attr"`
+	} `xml:"CipherData>CipherReference"`
+}
+
+// isFontObfuscation reports whether an algorithm is a font obfuscation
+// algorithm rather than real encryption
+func isFontObfuscation(algorithm string) bool {
+	return algorithm == algorithmIDPFFont || algorithm == algorithmAdobeFont
+}
+
+// parseEncryption parses META-INF/encryption.xml, if present
+//
+// Every encrypted resource is recorded with its algorithm. If any resource
+// is encrypted with an algorithm other than font obfuscation, the book is
+// marked as Encrypted.
+func (e *Epub) parseEncryption() error {
+	if _, ok := e.findFile("META-INF/encryption.xml"); !ok {
+		return nil
+	}
+
+	data, err := e.getFile("META-INF/encryption.xml")
+	if err != nil {
+		return err
+	}
+
+	var enc encryptionXML
+	if err := xml.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+
+	e.encryption = make(map[string]string)
+	for _, ed := range enc.EncryptedData {
+		uri := strings.TrimSpace(ed.CipherReference.URI)
+		if uri == "" {
+			continue
+		}
+
+		// URIs are relative to the root of the container and may be
+		// percent-encoded; resources are keyed by their entry name
+		name, ok := e.findFile(cleanZipPath(uri))
+		if !ok {
+			name = cleanZipPath(unescapeHref(uri))
+		}
+		e.encryption[name] = ed.Method.Algorithm
+
+		if !isFontObfuscation(ed.Method.Algorithm) {
+			e.Encrypted = true
+		}
+	}
+
+	return nil
+}
+
+// IsObfuscated reports whether a file in the EPUB is an obfuscated font
+//
+// The path is relative to the root of the EPUB, as for GetFileReader.
+// GetFileReader transparently de-obfuscates such files; callers that need the
+// raw obfuscated bytes can read them with the open function WalkFiles passes
+// for the file, which works for every kind of source.
+func (e *Epub) IsObfuscated(path string) bool {
+	name, ok := e.findFile(path)
+	return ok && isFontObfuscation(e.encryption[name])
+}
+
+// cleanZipPath normalizes a path within the EPUB archive
+func cleanZipPath(name string) string {
+	return path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "/"))
+}
+
+// obfuscationKey returns the key and the length of the obfuscated prefix for
+// a font obfuscated with the given algorithm, derived from the package's
+// unique identifier
+func (e *Epub) obfuscationKey(algorithm string) ([]byte, int, error) {
+	switch algorithm {
+	case algorithmIDPFFont:
+		// The key is the SHA-1 digest of the identifier without whitespace
+		id := strings.Map(func(r rune) rune {
+			if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+				return -1
+			}
+			return r
+		}, e.Metadata.Identifier)
+		sum := sha1.Sum([]byte(id))
+		return sum[:], 1040, nil
+	case algorithmAdobeFont:
+		// The key is the 16 bytes of the UUID identifier
+		id := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(e.Metadata.Identifier)), "urn:uuid:")
+		key, err := hex.DecodeString(strings.ReplaceAll(id, "-", ""))
+		if err != nil || len(key) != 16 {
+			return nil, 0, errors.New("identifier is not a UUID; cannot derive Adobe font key")
+		}
+		return key, 1024, nil
+	}
+	return nil, 0, errors.New("unsupported obfuscation algorithm: " + algorithm)
+}
+
+// deobfuscate wraps r so that the obfuscated prefix of the font stored in
+// the named entry is restored while reading. Files that are not obfuscated
+// are returned as is.
+func (e *Epub) deobfuscate(name string, r io.ReadCloser) (io.ReadCloser, error) {
+	algorithm := e.encryption[name]
+	if !isFontObfuscation(algorithm) {
+		return r, nil
+	}
+
+	key, limit, err := e.obfuscationKey(algorithm)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	return readCloser{Reader: &xorReader{r: r, key: key, limit: limit}, Closer: r}, nil
+}
+
+// xorReader XORs the first limit bytes read from r with a repeating key
+type xorReader struct {
+	r     io.Reader
+	key   []byte
+	limit int
+	pos   int
+}
+
+// Read implements io.Reader
+func (x *xorReader) Read(p []byte) (int, error) {
+	n, err := x.r.Read(p)
+	for i := 0; i < n && x.pos < x.limit; i++ {
+		p[i] ^= x.key[x.pos%len(x.key)]
+		x.pos++
+	}
+	return n, err
+}
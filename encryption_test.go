@@ -0,0 +1,165 @@
+package epub
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testEncryptionXML returns an encryption.xml encrypting uri with algorithm
+func testEncryptionXML(uri, algorithm string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<encryption xmlns="urn:oasis:names:tc:opendocument:xmlns:container" xmlns:enc="http://www.w3.org/2001/04/xmlenc#">
+	<enc:EncryptedData>
+		<enc:EncryptionMethod Algorithm="` + algorithm + `"/>
+		<enc:CipherData><enc:CipherReference URI="` + uri + `"/></enc:CipherData>
+	</enc:EncryptedData>
+</encryption>`
+}
+
+func TestOpen_DRMProtected(t *testing.T) {
+	files := testEPUB3Files()
+	files["META-INF/encryption.xml"] = testEncryptionXML("OEBPS/text/one.xhtml", "http://www.w3.org/2001/04/xmlenc#aes128-cbc")
+
+	epub, err := OpenBytes(buildTestZip(t, files))
+	if !errors.Is(err, ErrDRMProtected) {
+		t.Fatalf("Expected ErrDRMProtected, got %v", err)
+	}
+
+	if epub == nil {
+		t.Fatal("Expected partially parsed EPUB alongside ErrDRMProtected")
+	}
+
+	if !epub.Encrypted {
+		t.Error("Expected Encrypted to be set")
+	}
+
+	if epub.GetTitle() != "Nav Test" {
+		t.Errorf("Expected metadata to remain accessible, got title %q", epub.GetTitle())
+	}
+}
+
+func TestOpen_DRMProtectedKeepsParseError(t *testing.T) {
+	files := testEPUB3Files()
+	files["META-INF/encryption.xml"] = testEncryptionXML("OEBPS/text/one.xhtml", "http://www.w3.org/2001/04/xmlenc#aes128-cbc")
+	files["OEBPS/content.opf"] = `<package><metadata>`
+
+	_, err := OpenBytes(buildTestZip(t, files))
+	if !errors.Is(err, ErrDRMProtected) {
+		t.Fatalf("Expected ErrDRMProtected, got %v", err)
+	}
+	var syntaxErr *xml.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the package document syntax error to be kept, got %v", err)
+	}
+}
+
+func TestOpen_FontObfuscationIsNotDRM(t *testing.T) {
+	files := testEPUB3Files()
+	files["META-INF/encryption.xml"] = testEncryptionXML("OEBPS/fonts/font.otf", algorithmIDPFFont)
+
+	epub, err := OpenBytes(buildTestZip(t, files))
+	if err != nil {
+		t.Fatalf("Expected font obfuscation not to be reported as DRM, got %v", err)
+	}
+
+	if epub.Encrypted {
+		t.Error("Expected Encrypted to be false for font obfuscation only")
+	}
+}
+
+func TestEpub_GetFileReader_DeobfuscatesFonts(t *testing.T) {
+	font := make([]byte, 3000)
+	for i := range font {
+		font[i] = byte(i * 7)
+	}
+
+	const identifier = "urn:uuid:12340000-0000-0000-0000-000000000000"
+	idpfKey := sha1.Sum([]byte(identifier))
+
+	tests := []struct {
+		algorithm string
+		key       []byte
+		limit     int
+	}{
+		{algorithmIDPFFont, idpfKey[:], 1040},
+		{algorithmAdobeFont, []byte{0x12, 0x34, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			obfuscated := make([]byte, len(font))
+			copy(obfuscated, font)
+			for i := 0; i < tt.limit; i++ {
+				obfuscated[i] ^= tt.key[i%len(tt.key)]
+			}
+
+			files := testEPUB3Files()
+			files["OEBPS/content.opf"] = strings.Replace(testNavOPF, "urn:uuid:1234", identifier, 1)
+			files["OEBPS/fonts/font.otf"] = string(obfuscated)
+			files["META-INF/encryption.xml"] = testEncryptionXML("OEBPS/fonts/font.otf", tt.algorithm)
+			epub := newTestEpub(t, files)
+
+			if !epub.IsObfuscated("OEBPS/fonts/font.otf") {
+				t.Error("Expected font to be reported as obfuscated")
+			}
+
+			if epub.IsObfuscated("OEBPS/text/one.xhtml") {
+				t.Error("Expected chapter not to be reported as obfuscated")
+			}
+
+			reader, err := epub.GetFileReader("OEBPS/fonts/font.otf")
+			if err != nil {
+				t.Fatalf("Failed to get font reader: %v", err)
+			}
+			defer reader.Close()
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("Failed to read font: %v", err)
+			}
+
+			if !bytes.Equal(got, font) {
+				t.Error("Expected de-obfuscated font to match the original")
+			}
+		})
+	}
+}
+
+func TestEpub_GetFileReader_DeobfuscatesEscapedFontNames(t *testing.T) {
+	font := []byte(strings.Repeat("font data ", 200))
+	key := sha1.Sum([]byte("urn:uuid:1234"))
+	obfuscated := make([]byte, len(font))
+	copy(obfuscated, font)
+	for i := 0; i < 1040; i++ {
+		obfuscated[i] ^= key[i%len(key)]
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/fonts/My Font.otf"] = string(obfuscated)
+	files["META-INF/encryption.xml"] = testEncryptionXML("OEBPS/fonts/My%20Font.otf", algorithmIDPFFont)
+	epub := newTestEpub(t, files)
+
+	for _, path := range []string{"OEBPS/fonts/My%20Font.otf", "OEBPS/fonts/My Font.otf"} {
+		if !epub.IsObfuscated(path) {
+			t.Errorf("Expected %s to be reported as obfuscated", path)
+		}
+
+		reader, err := epub.GetFileReader(path)
+		if err != nil {
+			t.Fatalf("Failed to get font reader for %s: %v", path, err)
+		}
+		got, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("Failed to read font %s: %v", path, err)
+		}
+		if !bytes.Equal(got, font) {
+			t.Errorf("Expected de-obfuscated font %s to match the original", path)
+		}
+	}
+}
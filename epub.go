@@ -43,6 +43,7 @@
 //		if err != nil {
 //			log.Fatal(err)
 //		}
+//		defer reader.Close()
 //
 //		// Copy the content to stdout
 //		_, err = io.Copy(os.Stdout, reader)
@@ -55,11 +56,18 @@ package epub
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"iter"
+	"net/url"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Epub represents an EPUB file
@@ -67,33 +75,106 @@ import (
 // The Epub struct contains the parsed contents of an EPUB file,
 // including its metadata, manifest, spine, and table of contents.
 // It also maintains a reference to the underlying zip.Reader for
-// accessing the raw file contents.
+// accessing the raw file contents. File is nil for books opened with OpenFS;
+// all methods read files through an internal source that abstracts over the
+// zip archive or file system, so they work the same either way.
+//
+// Once opened, an Epub is safe for concurrent use by multiple goroutines:
+// its methods only read the parsed structure, and every read of file content
+// opens its own reader on the underlying archive. Callers that modify the
+// exported fields must synchronize those writes themselves.
 type Epub struct {
 	File     *zip.Reader
 	RootFile string
+	Version  string
 	Metadata Metadata
 	Manifest []Item
 	Spine    []ItemRef
+	Guide    []Reference
 	TOC      *NCX
 
+	// Encrypted reports whether the content is encrypted with DRM, as opposed
+	// to only having obfuscated fonts
+	Encrypted bool
+
+	// Encryption algorithms of the resources listed in encryption.xml, keyed
+	// by zip path
+	encryption map[string]string
+
+	// Rootfiles declared by container.xml, one per rendition
+	rootfiles []Rootfile
+
+	// ID of the NCX item named by the spine's toc attribute
+	spineTOC string
+
+	// The spine's page-progression-direction attribute
+	pageDirection string
+
+	// Landmarks of the EPUB 3 navigation document
+	landmarks []Landmark
+
+	// Path of the document the TOC was parsed from, used to resolve its hrefs
+	tocPath string
+
+	// Source of the TOC requested with WithTOCSource, and the one used
+	tocSource     TOCSource
+	usedTOCSource TOCSource
+
+	// Files of the EPUB, read from File or another backend
+	src source
+
+	// Cache of decompressed files, nil unless enabled with WithCacheSize
+	cache *fileCache
+
+	// Context of the parse in progress, nil once the Epub has been parsed
+	parseCtx context.Context
+
+	// Largest decompressed size of a file read from the archive, set with
+	// WithMaxContentLength when opening; zero means no limit
+	maxFileSize int64
+
 	// Store the ReadCloser for closing when needed
 	readCloser io.Closer
 }
 
 // Metadata represents the metadata of an EPUB
+//
+// Creator holds the name of the first creator for convenience; the complete
+// list of creators, including their roles, is available in Creators. Subject
+// and Language likewise hold the first of Subjects and Languages, Publisher
+// the first of Publishers, and Contributor the name of the first of
+// Contributors.
+// Similarly, Identifier holds the package's unique identifier while all
+// declared identifiers are available in Identifiers, and Date holds the
+// publication date while all dates are available in Dates. Title holds the
+// main title among Titles.
+//
+// The EPUB 3 refinements of titles, creators, contributors and identifiers
+// are attached to them in their Refinements fields.
 type Metadata struct {
-	Title       string `xml:"title"`
-	Creator     string `xml:"creator"`
-	Subject     string `xml:"subject"`
-	Description string `xml:"description"`
-	Publisher   string `xml:"publisher"`
-	Contributor string `xml:"contributor"`
-	Date        string `xml:"date"`
-	Type        string `xml:"type"`
-	Format      string `xml:"format"`
-	Identifier  string `xml:"identifier"`
-	Language    string `xml:"language"`
-	Rights      string `xml:"rights"`
+	Title        string       `xml:"-"`
+	Titles       []Title      `xml:"title"`
+	Creator      string       `xml:"-"`
+	Creators     []Creator    `xml:"creator"`
+	Subject      string       `xml:"-"`
+	Subjects     []string     `xml:"subject"`
+	Description  string       `xml:"description"`
+	Publisher    string       `xml:"-"`
+	Publishers   []string     `xml:"publisher"`
+	Contributor  string       `xml:"-"`
+	Contributors []Creator    `xml:"contributor"`
+	Date         string       `xml:"-"`
+	Dates        []Date       `xml:"date"`
+	Type         string       `xml:"type"`
+	Format       string       `xml:"format"`
+	Identifier   string       `xml:"-"`
+	Identifiers  []Identifier `xml:"identifier"`
+	Language     string       `xml:"-"`
+	Languages    []string     `xml:"language"`
+	Rights       string       `xml:"rights"`
+	Sources      []string     `xml:"source"`
+	Relations    []string     `xml:"relation"`
+	Meta         []Meta       `xml:"meta"`
 }
 
 // Container represents the container.xml file structure
@@ -102,56 +183,194 @@ type Container struct {
 }
 
 // Rootfile represents root file information
+//
+// Each rootfile is a rendition of the book. The remaining fields hold the
+// rendition selection attributes of EPUB 3 Multiple-Rendition Publications,
+// which are empty for ordinary books.
 type Rootfile struct {
-	FullPath  string `xml:"full-path,attr"`
-	MediaType string `xml:"media-type,attr"`
+	FullPath   string `xml:"full-path,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Label      string `xml:"label,attr"`
+	Layout     string `xml:"layout,attr"`
+	Language   string `xml:"language,attr"`
+	Media      string `xml:"media,attr"`
+	AccessMode string `xml:"accessMode,attr"`
 }
 
 // Package represents the package document structure
 type Package struct {
-	Metadata Metadata  `xml:"metadata"`
-	Manifest []Item    `xml:"manifest>item"`
-	Spine    []ItemRef `xml:"spine>itemref"`
+	Version          string      `xml:"version,attr"`
+	UniqueIdentifier string      `xml:"unique-identifier,attr"`
+	Metadata         Metadata    `xml:"metadata"`
+	Manifest         []Item      `xml:"manifest>item"`
+	Spine            []ItemRef   `xml:"spine>itemref"`
+	Guide            []Reference `xml:"guide>reference"`
 }
 
 // Item represents an item in the manifest
 type Item struct {
-	ID        string `xml:"id,attr"`
-	Href      string `xml:"href,attr"`
-	MediaType string `xml:"media-type,attr"`
+	ID           string `xml:"id,attr"`
+	Href         string `xml:"href,attr"`
+	MediaType    string `xml:"media-type,attr"`
+	Properties   string `xml:"properties,attr"`
+	MediaOverlay string `xml:"media-overlay,attr"`
+}
+
+// HasProperty reports whether the item's space-separated properties list
+// contains prop, such as "nav", "cover-image", "scripted", "mathml" or "svg"
+func (i Item) HasProperty(prop string) bool {
+	for _, p := range strings.Fields(i.Properties) {
+		if p == prop {
+			return true
+		}
+	}
+	return false
 }
 
 // ItemRef represents an item reference in the spine
 type ItemRef struct {
-	IDRef  string `xml:"idref,attr"`
-	Linear string `xml:"linear,attr"`
+	IDRef      string `xml:"idref,attr"`
+	Linear     string `xml:"linear,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// IsLinear reports whether the item is part of the primary reading order
+//
+// Items are linear unless their linear attribute is "no".
+func (r ItemRef) IsLinear() bool {
+	return !strings.EqualFold(strings.TrimSpace(r.Linear), "no")
+}
+
+// Reference represents a reference in the EPUB 2 guide
+//
+// Href is relative to the directory of the package document, like the hrefs
+// of manifest items.
+type Reference struct {
+	Type  string `xml:"type,attr"`
+	Title string `xml:"title,attr"`
+	Href  string `xml:"href,attr"`
 }
 
 // NCX represents the NCX file structure (table of contents)
 type NCX struct {
-	Title  string     `xml:"docTitle>text"`
-	NavMap []NavPoint `xml:"navMap>navPoint"`
+	Title    string       `xml:"docTitle>text"`
+	NavMap   []NavPoint   `xml:"navMap>navPoint"`
+	PageList []PageTarget `xml:"pageList>pageTarget"`
 }
 
 // NavPoint represents a navigation point (chapter)
+//
+// Src is the src attribute of the navPoint's <content> element, relative to
+// the document the navigation was parsed from. Labels holds the text of
+// every navLabel in document order; multilingual NCX files carry one label
+// per language, the first being the default.
 type NavPoint struct {
-	ID        string     `xml:"id,attr"`
-	PlayOrder string     `xml:"playOrder,attr"`
-	Label     string     `xml:"navLabel>text"`
-	Content   string     `xml:"content"`
-	Src       string     `xml:"content,attr"`
-	NavPoints []NavPoint `xml:"navPoint"`
+	ID        string          `xml:"id,attr"`
+	PlayOrder string          `xml:"playOrder,attr"`
+	Labels    []LocalizedText `xml:"-"`
+	Src       string          `xml:"-"`
+	NavPoints []NavPoint      `xml:"navPoint"`
+}
+
+// LocalizedText is a text in the language given by its xml:lang attribute,
+// or in the default language of the document if Lang is empty
+type LocalizedText struct {
+	Lang string
+	Text string
+}
+
+// Label returns the default label of the navigation point, which is its
+// first label, or an empty string if it has none
+func (n NavPoint) Label() string {
+	if len(n.Labels) > 0 {
+		return n.Labels[0].Text
+	}
+	return ""
+}
+
+// UnmarshalXML decodes an NCX navPoint, taking Src from the src attribute
+// of its <content> element and Labels from its navLabel elements
+func (n *NavPoint) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type navPoint NavPoint
+	var raw struct {
+		navPoint
+		NavLabels []struct {
+			Lang  string `xml:"lang,attr"`
+			Texts []struct {
+				Lang string `xml:"lang,attr"`
+				Text string `xml:",chardata"`
+			} `xml:"text"`
+		} `xml:"navLabel"`
+		ContentSrc struct {
+			Src string `xml:"src,attr"`
+		} `xml:"content"`
+	}
+
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	*n = NavPoint(raw.navPoint)
+	n.Src = raw.ContentSrc.Src
+	for _, label := range raw.NavLabels {
+		for _, text := range label.Texts {
+			lang := text.Lang
+			if lang == "" {
+				lang = label.Lang
+			}
+			n.Labels = append(n.Labels, LocalizedText{Lang: lang, Text: text.Text})
+		}
+	}
+	return nil
+}
+
+// PageTarget represents a page of the print edition in the page list
+//
+// Value is the page number or name and Type is one of "front", "normal" or
+// "special" when given. Src locates the page break, relative to the document
+// the page list was parsed from.
+type PageTarget struct {
+	ID        string `xml:"id,attr"`
+	Value     string `xml:"value,attr"`
+	Type      string `xml:"type,attr"`
+	PlayOrder string `xml:"playOrder,attr"`
+	Label     string `xml:"navLabel>text"`
+	Src       string `xml:"-"`
+}
+
+// UnmarshalXML decodes an NCX pageTarget, taking Src from the src attribute
+// of its <content> element
+func (p *PageTarget) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type pageTarget PageTarget
+	var raw struct {
+		pageTarget
+		ContentSrc struct {
+			Src string `xml:"src,attr"`
+		} `xml:"content"`
+	}
+
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	*p = PageTarget(raw.pageTarget)
+	p.Src = raw.ContentSrc.Src
+	return nil
 }
 
 // Chapter represents a book chapter
 //
 // A Chapter contains the title, content, and order of a chapter
 // in the EPUB file. Chapters are extracted based on the spine
-// order defined in the EPUB package document.
+// order defined in the EPUB package document. Href is the href of the
+// chapter's manifest item, relative to the package document directory, and
+// ID is the idref of its spine item, which is also the manifest item's ID.
 type Chapter struct {
 	Title   string
 	Content string
 	Order   int
+	Href    string
+	ID      string
 }
 
 // Open opens and parses an EPUB file from a file path
@@ -164,6 +383,13 @@ type Chapter struct {
 // It is the caller's responsibility to call Close on the returned Epub when
 // finished with it to free up resources.
 //
+// If the book's content is encrypted with DRM, the partially parsed Epub is
+// returned together with ErrDRMProtected, so that its metadata can still be
+// inspected. Obfuscated fonts alone are not reported as DRM.
+//
+// Options such as WithCacheSize configure the returned Epub. A context given
+// with WithContext is used as with OpenContext.
+//
 // Example:
 //
 //	e, err := epub.Open("book.epub")
@@ -173,28 +399,44 @@ type Chapter struct {
 //	defer e.Close()
 //
 //	title := e.GetTitle()
-func Open(path string) (*Epub, error) {
-	reader, err := zip.OpenReader(path)
-	if err != nil {
-		return nil, err
-	}
-
-	epub := &Epub{
-		File:       &reader.Reader,
-		readCloser: reader,
-	}
+func Open(path string, opts ...Option) (*Epub, error) {
+	return OpenContext(applyOptions(opts...).ctx, path, opts...)
+}
 
-	if err := epub.parseContainer(); err != nil {
-		epub.Close()
+// OpenContext opens and parses an EPUB file from a file path with a context
+//
+// OpenContext behaves like Open, but checks the context between the parsing
+// stages (container, package document, and table of contents) and aborts with
+// the context's error if it has been cancelled or its deadline has passed.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	e, err := epub.OpenContext(ctx, "book.epub")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer e.Close()
+func OpenContext(ctx context.Context, path string, opts ...Option) (*Epub, error) {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	if err := epub.parsePackage(); err != nil {
-		epub.Close()
-		return nil, err
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, zipError(err)
 	}
 
-	if err := epub.parseTOC(); err != nil {
+	epub := newEpub(newZipSource(&reader.Reader), opts)
+	epub.File = &reader.Reader
+	epub.readCloser = reader
+
+	if err := epub.parse(ctx); err != nil {
+		if errors.Is(err, ErrDRMProtected) {
+			return epub, err
+		}
 		epub.Close()
 		return nil, err
 	}
@@ -202,6 +444,30 @@ func Open(path string) (*Epub, error) {
 	return epub, nil
 }
 
+// OpenWithTimeout opens and parses an EPUB file, giving up after a timeout
+//
+// OpenWithTimeout behaves like OpenContext with a context whose deadline is d
+// from now, derived from the context given with WithContext, if any. The
+// deadline is enforced while files are being decompressed as well as between
+// the parsing stages, so a hostile archive cannot stall parsing indefinitely.
+// As with every constructor, the files read during parsing are also limited
+// to 64 MiB each, or to the limit given with WithMaxContentLength. The
+// timeout only applies to parsing; the returned Epub can be used for as long
+// as needed.
+//
+// Example:
+//
+//	e, err := epub.OpenWithTimeout("book.epub", 5*time.Second)
+//	if errors.Is(err, context.DeadlineExceeded) {
+//		log.Fatal("book took too long to parse")
+//	}
+func OpenWithTimeout(path string, d time.Duration, opts ...Option) (*Epub, error) {
+	ctx, cancel := context.WithTimeout(applyOptions(opts...).ctx, d)
+	defer cancel()
+
+	return OpenContext(ctx, path, opts...)
+}
+
 // New creates and parses an EPUB from a zip.Reader
 //
 // The New function takes a zip.Reader and returns a pointer to an
@@ -236,20 +502,23 @@ func Open(path string) (*Epub, error) {
 //		log.Fatal(err)
 //	}
 //	defer e.Close()
-func New(r *zip.Reader) (*Epub, error) {
-	epub := &Epub{
-		File: r,
-	}
-
-	if err := epub.parseContainer(); err != nil {
-		return nil, err
-	}
+func New(r *zip.Reader, opts ...Option) (*Epub, error) {
+	return NewContext(applyOptions(opts...).ctx, r, opts...)
+}
 
-	if err := epub.parsePackage(); err != nil {
-		return nil, err
-	}
+// NewContext creates and parses an EPUB from a zip.Reader with a context
+//
+// NewContext behaves like New, but checks the context between the parsing
+// stages and aborts with the context's error if it has been cancelled or its
+// deadline has passed.
+func NewContext(ctx context.Context, r *zip.Reader, opts ...Option) (*Epub, error) {
+	epub := newEpub(newZipSource(r), opts)
+	epub.File = r
 
-	if err := epub.parseTOC(); err != nil {
+	if err := epub.parse(ctx); err != nil {
+		if errors.Is(err, ErrDRMProtected) {
+			return epub, err
+		}
 		return nil, err
 	}
 
@@ -278,56 +547,275 @@ func New(r *zip.Reader) (*Epub, error) {
 //		log.Fatal(err)
 //	}
 //	defer e.Close()
-func NewReader(r io.Reader) (*Epub, error) {
+func NewReader(r io.Reader, opts ...Option) (*Epub, error) {
 	// Read all data into memory to provide random access
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a reader that implements ReaderAt
-	readerAt := bytes.NewReader(data)
+	return OpenBytes(data, opts...)
+}
 
-	// Create a zip reader
-	zipReader, err := zip.NewReader(readerAt, int64(len(data)))
+// OpenReader creates and parses an EPUB from an io.ReaderAt
+//
+// The OpenReader function reads the EPUB archive of the given size from r
+// without buffering it in memory. The caller retains ownership of r and must
+// keep it open for as long as the returned Epub is in use; Close on the
+// returned Epub does not close r.
+//
+// Example:
+//
+//	f, err := os.Open("book.epub")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	stat, err := f.Stat()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	e, err := epub.OpenReader(f, stat.Size())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func OpenReader(r io.ReaderAt, size int64, opts ...Option) (*Epub, error) {
+	zipReader, err := zip.NewReader(r, size)
 	if err != nil {
-		return nil, err
+		return nil, zipError(err)
 	}
 
-	// Create epub
-	epub := &Epub{
-		File: zipReader,
+	return New(zipReader, opts...)
+}
+
+// OpenBytes creates and parses an EPUB from its raw bytes
+//
+// This is useful when the EPUB has already been loaded into memory, for
+// example after downloading it over HTTP. The data must not be modified
+// while the returned Epub is in use.
+//
+// Example:
+//
+//	resp, err := http.Get("https://example.com/book.epub")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer resp.Body.Close()
+//
+//	data, err := io.ReadAll(resp.Body)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	e, err := epub.OpenBytes(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func OpenBytes(data []byte, opts ...Option) (*Epub, error) {
+	return OpenReader(bytes.NewReader(data), int64(len(data)), opts...)
+}
+
+// zipError marks errors caused by data that is not a zip archive as
+// ErrInvalidEPUB
+func zipError(err error) error {
+	if errors.Is(err, zip.ErrFormat) {
+		return fmt.Errorf("%w: %w", ErrInvalidEPUB, err)
 	}
+	return err
+}
 
-	if err := epub.parseContainer(); err != nil {
+// OpenFS creates and parses an EPUB from an unpacked directory tree
+//
+// The root of fsys must contain the files of the EPUB, such as
+// META-INF/container.xml, as they would appear in the zip archive. This
+// makes it possible to read books extracted to a directory with os.DirFS or
+// embedded with go:embed. The File field of the returned Epub is nil.
+//
+// Example:
+//
+//	//go:embed book
+//	var book embed.FS
+//
+//	fsys, err := fs.Sub(book, "book")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	e, err := epub.OpenFS(fsys)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func OpenFS(fsys fs.FS, opts ...Option) (*Epub, error) {
+	options := applyOptions(opts...)
+	if err := options.checkContext(); err != nil {
 		return nil, err
 	}
 
-	if err := epub.parsePackage(); err != nil {
+	src, err := newFSSource(fsys)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := epub.parseTOC(); err != nil {
+	epub := newEpub(src, opts)
+	if err := epub.parse(options.ctx); err != nil {
+		if errors.Is(err, ErrDRMProtected) {
+			return epub, err
+		}
 		return nil, err
 	}
 
 	return epub, nil
 }
 
+// newEpub returns an unparsed Epub reading from src, configured by the
+// open-time options
+func newEpub(src source, opts []Option) *Epub {
+	options := applyOptions(opts...)
+	return &Epub{
+		src:         src,
+		cache:       newFileCache(options.CacheSize),
+		maxFileSize: options.MaxContentLength,
+		tocSource:   options.TOCSource,
+	}
+}
+
+// parse parses the container file, encryption information, package document,
+// and table of contents, checking the context before each stage
+//
+// If the content is encrypted with DRM, parsing continues as far as possible
+// and ErrDRMProtected is returned, wrapping the error that stopped parsing,
+// if any. While parsing, reads from the archive are
+// aborted once the context is done, and files larger than maxParseFileSize
+// are rejected.
+func (e *Epub) parse(ctx context.Context) error {
+	e.parseCtx = ctx
+	defer func() { e.parseCtx = nil }()
+
+	stages := []func() error{
+		e.parseContainer,
+		e.parseEncryption,
+		e.parsePackage,
+		e.parseTOC,
+	}
+
+	for _, stage := range stages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := stage(); err != nil {
+			if e.Encrypted {
+				// Later documents such as the TOC may themselves be encrypted,
+				// but the error may just as well have another cause
+				return fmt.Errorf("%w: %w", ErrDRMProtected, err)
+			}
+			return err
+		}
+	}
+
+	if e.Encrypted {
+		return ErrDRMProtected
+	}
+	return nil
+}
+
 // parseContainer parses the META-INF/container.xml file
+//
+// If META-INF/container.xml is missing, a package document that is the only
+// .opf file in the archive is used instead.
 func (e *Epub) parseContainer() error {
 	containerFile, err := e.getFile("META-INF/container.xml")
+	if errors.Is(err, ErrFileNotFound) {
+		return e.findSingleRootFile()
+	}
 	if err != nil {
 		return err
 	}
 
 	var container Container
 	if err := xml.Unmarshal(containerFile, &container); err != nil {
-		return err
+		return fmt.Errorf("%w: failed to parse container.xml: %w", ErrInvalidEPUB, err)
+	}
+
+	if len(container.Rootfiles) == 0 {
+		return fmt.Errorf("%w: container.xml does not declare a rootfile", ErrInvalidEPUB)
+	}
+	e.rootfiles = container.Rootfiles
+	e.RootFile = container.Rootfiles[0].FullPath
+
+	return nil
+}
+
+// findSingleRootFile sets RootFile to the only .opf file in the archive
+//
+// An error wrapping ErrInvalidEPUB is returned if the archive contains no
+// package document or more than one.
+func (e *Epub) findSingleRootFile() error {
+	var candidates []string
+	for _, name := range e.src.list() {
+		if strings.EqualFold(path.Ext(name), ".opf") {
+			candidates = append(candidates, name)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return fmt.Errorf("%w: container.xml is missing and no package document was found", ErrInvalidEPUB)
+	case 1:
+		e.RootFile = candidates[0]
+		e.rootfiles = []Rootfile{{FullPath: candidates[0], MediaType: "application/oebps-package+xml"}}
+		return nil
+	default:
+		return fmt.Errorf("%w: container.xml is missing and %d package documents were found", ErrInvalidEPUB, len(candidates))
+	}
+}
+
+// GetRenditions returns the renditions declared in container.xml
+//
+// Most books have a single rendition. EPUB 3 Multiple-Rendition Publications
+// may declare several, for example a fixed-layout and a reflowable one,
+// distinguished by their label, layout, language and media attributes. The
+// first rendition is used when the book is opened.
+func (e *Epub) GetRenditions() []Rootfile {
+	return e.rootfiles
+}
+
+// SelectRendition switches the Epub to the rendition at the given index
+//
+// The package document and table of contents of the chosen rendition are
+// parsed and replace the metadata, manifest, spine, guide and TOC of the
+// current one. If parsing fails, the Epub is left unchanged and the error is
+// returned. SelectRendition must not be called concurrently with other
+// methods.
+//
+// Example:
+//
+//	for i, rendition := range e.GetRenditions() {
+//		if rendition.Layout == "pre-paginated" {
+//			if err := e.SelectRendition(i); err != nil {
+//				log.Fatal(err)
+//			}
+//		}
+//	}
+func (e *Epub) SelectRendition(index int) error {
+	if index < 0 || index >= len(e.rootfiles) {
+		return fmt.Errorf("rendition index out of range: %d", index)
 	}
 
-	if len(container.Rootfiles) > 0 {
-		e.RootFile = container.Rootfiles[0].FullPath
+	saved := *e
+	e.RootFile = e.rootfiles[index].FullPath
+	e.TOC = nil
+	e.tocPath = ""
+	e.usedTOCSource = TOCSourceAuto
+	e.landmarks = nil
+	e.spineTOC = ""
+	e.pageDirection = ""
+
+	for _, stage := range []func() error{e.parsePackage, e.parseTOC} {
+		if err := stage(); err != nil {
+			*e = saved
+			return err
+		}
 	}
 
 	return nil
@@ -337,117 +825,387 @@ func (e *Epub) parseContainer() error {
 func (e *Epub) parsePackage() error {
 	packageFile, err := e.getFile(e.RootFile)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: failed to read package document: %w", ErrInvalidEPUB, err)
 	}
 
 	var pkg Package
 	if err := xml.Unmarshal(packageFile, &pkg); err != nil {
-		return err
+		return fmt.Errorf("%w: failed to parse package document: %w", ErrInvalidEPUB, err)
 	}
 
+	e.Version = strings.TrimSpace(pkg.Version)
 	e.Metadata = pkg.Metadata
+	e.Metadata.normalize(pkg.UniqueIdentifier)
 	e.Manifest = pkg.Manifest
 	e.Spine = pkg.Spine
+	e.Guide = pkg.Guide
+
+	// The toc and page-progression-direction attributes live on the spine
+	// element itself, which Package only models through its itemrefs
+	var spine struct {
+		Spine struct {
+			TOC       string `xml:"toc,attr"`
+			Direction string `xml:"page-progression-direction,attr"`
+		} `xml:"spine"`
+	}
+	if err := xml.Unmarshal(packageFile, &spine); err == nil {
+		e.spineTOC = strings.TrimSpace(spine.Spine.TOC)
+		e.pageDirection = strings.TrimSpace(spine.Spine.Direction)
+	}
 
 	return nil
 }
 
-// parseTOC parses the NCX table of contents file
-func (e *Epub) parseTOC() error {
-	// Try to find the NCX file first (EPUB 2.0)
-	ncxItem := e.findItemByMediaType("application/x-dtbncx+xml")
-	if ncxItem != nil {
-		// Get NCX file content
-		ncxPath := filepath.Join(filepath.Dir(e.RootFile), ncxItem.Href)
-		ncxData, err := e.getFile(ncxPath)
-		if err != nil {
-			return err
+// findNCXItem finds the NCX file in the manifest
+//
+// The item named by the spine's toc attribute is preferred, since some books
+// declare their NCX under a nonstandard media type. Otherwise the first item
+// with the NCX media type is returned.
+func (e *Epub) findNCXItem() *Item {
+	if e.spineTOC != "" {
+		if item := e.findItemByID(e.spineTOC); item != nil {
+			return item
 		}
+	}
+	return e.findItemByMediaType("application/x-dtbncx+xml")
+}
 
-		// Parse NCX
-		var ncx NCX
-		if err := xml.Unmarshal(ncxData, &ncx); err != nil {
-			return err
-		}
+// parseTOC parses the table of contents
+//
+// The EPUB 3 navigation document is preferred when present. If it is missing,
+// cannot be read, or contains no toc entries, the EPUB 2 NCX file is used.
+// Without an NCX file, such a navigation document leaves the book without a
+// TOC. WithTOCSource restricts parsing to one of the two, in which case the
+// errors of the chosen source are returned.
+func (e *Epub) parseTOC() error {
+	switch e.tocSource {
+	case TOCSourceNav:
+		return e.parseNavTOC()
+	case TOCSourceNCX:
+		// The navigation document still provides the landmarks
+		e.parseNavTOC()
+		e.TOC, e.tocPath, e.usedTOCSource = nil, "", TOCSourceAuto
+		return e.parseNCXTOC()
+	}
 
-		e.TOC = &ncx
+	if err := e.parseNavTOC(); err == nil && e.TOC != nil {
 		return nil
 	}
 
-	// For EPUB 3.0, try to find the navigation document
-	// Look for item with properties="nav"
-	for _, item := range e.Manifest {
-		// In EPUB 3, the navigation document is identified by properties="nav"
-		// We'll need to extend the Item struct to support this, but for now
-		// we can look for HTML files that might be the navigation document
-		if strings.Contains(item.MediaType, "html") {
-			// Try to parse as navigation document - simplified approach
-			// A full implementation would parse the HTML nav structure
-			continue
-		}
+	// Fall back to the NCX file (EPUB 2.0)
+	if e.findNCXItem() != nil {
+		return e.parseNCXTOC()
 	}
 
 	// If no TOC found, that's okay - not all EPUBs have a traditional TOC
 	return nil
 }
 
-// getFile gets the content of a file from the EPUB by path
-func (e *Epub) getFile(path string) ([]byte, error) {
-	path = filepath.ToSlash(path)
+// parseNCXTOC parses the EPUB 2 NCX file, if any
+func (e *Epub) parseNCXTOC() error {
+	ncxItem := e.findNCXItem()
+	if ncxItem == nil {
+		return nil
+	}
 
-	for _, file := range e.File.File {
-		if filepath.ToSlash(file.Name) == path {
-			rc, err := file.Open()
-			if err != nil {
-				return nil, err
-			}
-			defer rc.Close()
+	// Get NCX file content
+	ncxPath := e.resolveHref(ncxItem.Href)
+	ncxData, err := e.getFile(ncxPath)
+	if err != nil {
+		return err
+	}
 
-			return io.ReadAll(rc)
-		}
+	// Parse NCX
+	var ncx NCX
+	if err := xml.Unmarshal(ncxData, &ncx); err != nil {
+		return err
 	}
 
-	return nil, fmt.Errorf("file not found: %s", path)
+	e.TOC = &ncx
+	e.tocPath = ncxPath
+	e.usedTOCSource = TOCSourceNCX
+	return nil
 }
 
-// findItemByID finds an item in the manifest by ID
-func (e *Epub) findItemByID(id string) *Item {
-	for _, item := range e.Manifest {
-		if item.ID == id {
-			return &item
-		}
+// parseNavTOC parses the toc nav of the EPUB 3 navigation document, if any
+func (e *Epub) parseNavTOC() error {
+	navItem := e.findNavItem()
+	if navItem == nil {
+		return nil
 	}
-	return nil
-}
 
-// findItemByMediaType finds an item in the manifest by media type
-func (e *Epub) findItemByMediaType(mediaType string) *Item {
-	for _, item := range e.Manifest {
-		if item.MediaType == mediaType {
-			return &item
-		}
+	navPath := e.resolveHref(navItem.Href)
+	navData, err := e.getFile(navPath)
+	if err != nil {
+		return err
 	}
-	return nil
-}
 
-// findItemByHref finds an item in the manifest by href
-func (e *Epub) findItemByHref(href string) *Item {
-	href = filepath.ToSlash(href)
-	for _, item := range e.Manifest {
-		if filepath.ToSlash(item.Href) == href {
-			return &item
-		}
+	landmarks, err := parseLandmarks(navData)
+	if err != nil {
+		return err
 	}
-	return nil
-}
+	for i := range landmarks {
+		landmarks[i].Href = e.navRelative(navPath, landmarks[i].Href)
+	}
+	e.landmarks = landmarks
 
-// GetTitle returns the book title
-//
-// This method returns the title of the EPUB book as defined in its metadata.
-// If no title is defined in the EPUB metadata, an empty string is returned.
-func (e *Epub) GetTitle() string {
-	return e.Metadata.Title
-}
+	points, err := parseNavDocument(navData, "toc")
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	pages, err := parseNavDocument(navData, "page-list")
+	if err != nil {
+		return err
+	}
+
+	e.TOC = &NCX{
+		Title:    e.Metadata.Title,
+		NavMap:   points,
+		PageList: navPageTargets(pages),
+	}
+	e.tocPath = navPath
+	e.usedTOCSource = TOCSourceNav
+	return nil
+}
+
+// maxParseFileSize is the largest decompressed size accepted for a file read
+// while parsing, such as container.xml, the package document or the NCX
+//
+// It guards against decompression bombs; legitimate metadata files are
+// orders of magnitude smaller.
+var maxParseFileSize int64 = 64 << 20
+
+// getFile gets the content of a file from the EPUB by path
+//
+// If caching is enabled with WithCacheSize, the cache is consulted before
+// the entry is decompressed. Files larger than the limit given with
+// WithMaxContentLength when opening the Epub are rejected with
+// ErrFileTooLarge; without such a limit, maxParseFileSize applies while the
+// Epub is being parsed. Reading also stops once the parse context is done.
+func (e *Epub) getFile(path string) ([]byte, error) {
+	name, ok := e.findFile(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, filepath.ToSlash(path))
+	}
+
+	if e.cache != nil {
+		if data, ok := e.cache.get(name); ok {
+			return data, nil
+		}
+	}
+
+	limit := e.maxFileSize
+	if limit <= 0 && e.parseCtx != nil {
+		limit = maxParseFileSize
+	}
+	if err := e.checkSize(name, limit); err != nil {
+		return nil, err
+	}
+
+	rc, err := e.src.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if e.parseCtx != nil {
+		r = contextReader{ctx: e.parseCtx, r: rc}
+	}
+	data, err := readAllLimited(r, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if e.cache != nil {
+		e.cache.add(name, data)
+	}
+	return data, nil
+}
+
+// checkSize rejects a file whose recorded size exceeds limit, if the source
+// knows its size and limit is positive
+func (e *Epub) checkSize(name string, limit int64) error {
+	if s, ok := e.src.(sizer); ok && limit > 0 {
+		if size, ok := s.size(name); ok && size > limit {
+			return fmt.Errorf("%w: %s", ErrFileTooLarge, name)
+		}
+	}
+	return nil
+}
+
+// readAllLimited reads r to the end, failing with ErrFileTooLarge once more
+// than limit bytes have been read
+//
+// A limit of zero or less reads without limit. The size recorded in a zip
+// entry's header is not trusted, so the limit is applied to the data
+// actually decompressed.
+func readAllLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, limit)
+	}
+	return data, nil
+}
+
+// limitedReader is an io.Reader that fails with ErrFileTooLarge instead of
+// returning more than n bytes
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.n+1 {
+		p = p[:r.n+1]
+	}
+	n, err := r.r.Read(p)
+	if int64(n) > r.n {
+		r.n = -1
+		return 0, fmt.Errorf("%w: more than the allowed size", ErrFileTooLarge)
+	}
+	r.n -= int64(n)
+	return n, err
+}
+
+// contextReader is an io.Reader that fails with the context's error once the
+// context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// findFile finds a file in the EPUB by path and returns its exact name
+//
+// Hrefs are frequently percent-encoded in the package document while the zip
+// entry name is not, so the decoded form of the path is tried first and the
+// raw form is used as a fallback. Paths that could escape the root of the
+// EPUB, such as ones containing ".." elements, are never found.
+func (e *Epub) findFile(path string) (string, bool) {
+	path = filepath.ToSlash(path)
+	if isUnsafePath(path) {
+		return "", false
+	}
+
+	candidates := []string{path}
+	if decoded := unescapeHref(path); decoded != path {
+		candidates = []string{decoded, path}
+	}
+
+	names := e.src.list()
+	for _, candidate := range candidates {
+		for _, name := range names {
+			if filepath.ToSlash(name) == candidate {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// unescapeHref decodes the percent-encoding of a href
+//
+// If the href is not validly encoded it is returned unchanged.
+func unescapeHref(href string) string {
+	decoded, err := url.PathUnescape(href)
+	if err != nil {
+		return href
+	}
+	return decoded
+}
+
+// RootFileDir returns the directory of the package document
+//
+// Hrefs in the package document, such as those of the manifest, are
+// relative to this directory, the content root of the book. The result is a
+// slash-separated zip path on every platform, as returned by path.Dir, so it
+// is "." if the package document is at the root of the EPUB. Join it with
+// path.Join rather than filepath.Join.
+//
+// Example:
+//
+//	cssPath := path.Join(e.RootFileDir(), "styles/main.css")
+//	reader, err := e.GetFileReader(cssPath)
+func (e *Epub) RootFileDir() string {
+	return path.Dir(filepath.ToSlash(e.RootFile))
+}
+
+// resolveHref converts a manifest or TOC href into a zip path
+//
+// Hrefs in the package document are relative to its directory. Zip entry
+// names always use forward slashes, so path rather than filepath semantics
+// are used on every platform. "./" and "../" segments are cleaned up, and
+// any ".." that would climb above the root of the archive is dropped.
+func (e *Epub) resolveHref(href string) string {
+	p := path.Join(e.RootFileDir(), filepath.ToSlash(href))
+	for p == ".." || strings.HasPrefix(p, "../") {
+		p = strings.TrimPrefix(strings.TrimPrefix(p, ".."), "/")
+	}
+	return p
+}
+
+// findItemByID finds an item in the manifest by ID
+//
+// Like the other findItemBy* helpers, it returns a pointer to the element of
+// e.Manifest itself rather than to a copy.
+func (e *Epub) findItemByID(id string) *Item {
+	for i := range e.Manifest {
+		if e.Manifest[i].ID == id {
+			return &e.Manifest[i]
+		}
+	}
+	return nil
+}
+
+// findItemByMediaType finds an item in the manifest by media type
+func (e *Epub) findItemByMediaType(mediaType string) *Item {
+	for i := range e.Manifest {
+		if e.Manifest[i].MediaType == mediaType {
+			return &e.Manifest[i]
+		}
+	}
+	return nil
+}
+
+// findItemByHref finds an item in the manifest by href
+func (e *Epub) findItemByHref(href string) *Item {
+	href = unescapeHref(path.Clean(filepath.ToSlash(href)))
+	for i := range e.Manifest {
+		if unescapeHref(path.Clean(filepath.ToSlash(e.Manifest[i].Href))) == href {
+			return &e.Manifest[i]
+		}
+	}
+	return nil
+}
+
+// GetTitle returns the book title
+//
+// This method returns the title of the EPUB book as defined in its metadata.
+// If the book has several titles, the one refined with title-type "main" is
+// returned, or the first title if none is; GetTitles returns all of them.
+// If no title is defined in the EPUB metadata, an empty string is returned.
+func (e *Epub) GetTitle() string {
+	return e.Metadata.Title
+}
 
 // GetAuthor returns the book author
 //
@@ -457,6 +1215,67 @@ func (e *Epub) GetAuthor() string {
 	return e.Metadata.Creator
 }
 
+// GetCreators returns all creators of the book
+//
+// This method returns every creator declared in the EPUB metadata in document
+// order, together with their role (e.g. "aut" for author, "trl" for translator)
+// and file-as sort name. Roles are taken from the EPUB 2 opf:role attribute or
+// from EPUB 3 <meta refines> refinements.
+//
+// Example:
+//
+//	for _, creator := range e.GetCreators() {
+//		fmt.Printf("%s (%s)\n", creator.Name, creator.Role)
+//	}
+func (e *Epub) GetCreators() []Creator {
+	return e.Metadata.Creators
+}
+
+// GetPublishers returns all publishers of the book
+//
+// Books produced by several publishers, such as co-editions, declare more
+// than one <dc:publisher> element; they are returned in document order.
+// Metadata.Publisher holds the first of them.
+func (e *Epub) GetPublishers() []string {
+	return e.Metadata.Publishers
+}
+
+// GetContributors returns all contributors of the book
+//
+// Contributors are people or organizations with a secondary role, such as
+// editors, illustrators or translators of a scholarly edition. Like
+// creators, they are returned in document order with their role and file-as
+// sort name, taken from the EPUB 2 opf: attributes or from EPUB 3 <meta
+// refines> refinements.
+//
+// Example:
+//
+//	for _, contributor := range e.GetContributors() {
+//		fmt.Printf("%s (%s)\n", contributor.Name, contributor.Role)
+//	}
+func (e *Epub) GetContributors() []Creator {
+	return e.Metadata.Contributors
+}
+
+// GetSources returns the resources the book is derived from
+//
+// Each <dc:source> element identifies a work the book is derived from in
+// whole or in part, such as the print edition an ebook was produced from,
+// usually by an identifier like an ISBN or URN. They are returned in
+// document order.
+func (e *Epub) GetSources() []string {
+	return e.Metadata.Sources
+}
+
+// GetRelations returns the resources related to the book
+//
+// Each <dc:relation> element refers to a related work, such as another
+// volume of a series or a different edition, as text or an identifier.
+// They are returned in document order.
+func (e *Epub) GetRelations() []string {
+	return e.Metadata.Relations
+}
+
 // GetDescription returns the book description
 //
 // This method returns the description of the EPUB book as defined in its metadata.
@@ -465,6 +1284,76 @@ func (e *Epub) GetDescription() string {
 	return e.Metadata.Description
 }
 
+// GetVersion returns the EPUB specification version of the book
+//
+// This method returns the version attribute of the package document,
+// e.g. "2.0" or "3.0". If the package document does not declare a version,
+// an empty string is returned.
+func (e *Epub) GetVersion() string {
+	return e.Version
+}
+
+// IsEPUB3 reports whether the book declares EPUB version 3.x
+//
+// Books without a version attribute are not considered EPUB 3.
+func (e *Epub) IsEPUB3() bool {
+	return strings.HasPrefix(e.Version, "3")
+}
+
+// GetIdentifier returns the unique identifier of the book
+//
+// This method returns the value of the identifier referenced by the package's
+// unique-identifier attribute, falling back to the first declared identifier.
+// If no identifier is defined, an empty string is returned.
+func (e *Epub) GetIdentifier() string {
+	return e.Metadata.Identifier
+}
+
+// GetIdentifiers returns all identifiers of the book
+//
+// Many EPUBs declare several identifiers, such as a UUID, an ISBN and a DOI,
+// distinguished by their scheme.
+//
+// Example:
+//
+//	for _, id := range e.GetIdentifiers() {
+//		fmt.Printf("%s: %s\n", id.Scheme, id.Value)
+//	}
+func (e *Epub) GetIdentifiers() []Identifier {
+	return e.Metadata.Identifiers
+}
+
+// GetISBN returns the ISBN of the book, if one is declared
+//
+// This method returns the first identifier whose scheme is ISBN, or whose
+// value looks like an ISBN-10 or ISBN-13. A "urn:isbn:" prefix is removed.
+// If no ISBN is found, an empty string is returned.
+func (e *Epub) GetISBN() string {
+	for _, id := range e.Metadata.Identifiers {
+		value := trimISBNPrefix(id.Value)
+		if strings.EqualFold(id.Scheme, "ISBN") || isISBN(value) {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetSubjects returns all subjects of the book
+//
+// Books commonly list several genres or keywords as subjects. Surrounding
+// whitespace is trimmed and empty subjects are dropped.
+func (e *Epub) GetSubjects() []string {
+	return e.Metadata.Subjects
+}
+
+// GetLanguages returns all languages of the book
+//
+// The first language is the book's primary language; bilingual editions list
+// the secondary languages after it.
+func (e *Epub) GetLanguages() []string {
+	return e.Metadata.Languages
+}
+
 // GetMetadata returns the complete metadata of the book
 //
 // This method returns the complete metadata struct of the EPUB book,
@@ -497,11 +1386,60 @@ func (e *Epub) GetItems() []Item {
 	return e.Manifest
 }
 
+// ItemsWithProperty returns the manifest items with the given property
+//
+// EPUB 3 marks special items with the manifest properties attribute, for
+// example the navigation document ("nav"), the cover image ("cover-image")
+// and documents containing scripts ("scripted"), MathML ("mathml") or SVG
+// ("svg"). Items are returned in manifest order; the result is nil if no item
+// has the property.
+//
+// Example:
+//
+//	for _, item := range e.ItemsWithProperty("scripted") {
+//		fmt.Println("scripted document:", item.Href)
+//	}
+func (e *Epub) ItemsWithProperty(prop string) []Item {
+	var items []Item
+	for _, item := range e.Manifest {
+		if item.HasProperty(prop) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetGuideReference returns the first guide reference of the given type
+//
+// EPUB 2 packages may contain a <guide> listing key locations of the book,
+// such as "cover", "toc" or "text" (the start of the reading content). The
+// type is matched case-insensitively. If the guide has no reference of the
+// given type, an error is returned.
+//
+// Example:
+//
+//	ref, err := e.GetGuideReference("text")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println("Reading starts at", ref.Href)
+func (e *Epub) GetGuideReference(refType string) (*Reference, error) {
+	for i := range e.Guide {
+		if strings.EqualFold(e.Guide[i].Type, refType) {
+			return &e.Guide[i], nil
+		}
+	}
+	return nil, fmt.Errorf("guide reference not found: %s", refType)
+}
+
 // GetCover returns a reader for the cover image of the EPUB, if one exists
 //
 // This method attempts to locate and return a reader for the cover image of the EPUB.
 // Not all EPUBs have a cover image, and the location of the cover can vary between
-// EPUB versions. If a cover image is found, an io.ReadCloser is returned which the
+// EPUB versions. The cover is looked up, in order, via the EPUB 2
+// <meta name="cover"> element, the EPUB 3 manifest item with
+// properties="cover-image", and finally a few commonly used item IDs.
+// If a cover image is found, an io.ReadCloser is returned which the
 // caller must close. If no cover is found, nil is returned with no error.
 //
 // Example:
@@ -518,25 +1456,268 @@ func (e *Epub) GetItems() []Item {
 //		fmt.Println("No cover image found")
 //	}
 func (e *Epub) GetCover() (io.ReadCloser, error) {
-	// Try to find cover by meta tag (EPUB 2.0 and 3.0 method)
-	// This would require parsing the metadata more thoroughly
+	coverPath, err := e.GetCoverPath()
+	if errors.Is(err, ErrNoCover) {
+		// If no cover found, return nil without error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return e.GetFileReader(coverPath)
+}
+
+// GetCoverPath returns the zip path of the cover image
+//
+// The cover is located in the same way as GetCover, but it is not opened.
+// The returned path is relative to the root of the EPUB and can be passed
+// directly to GetFileReader. If the EPUB has no cover image, ErrNoCover is
+// returned.
+func (e *Epub) GetCoverPath() (string, error) {
+	item := e.findCoverItem()
+	if item == nil {
+		return "", ErrNoCover
+	}
+	return e.resolveHref(item.Href), nil
+}
+
+// findCoverItem finds the manifest item of the cover image
+func (e *Epub) findCoverItem() *Item {
+	// EPUB 2: <meta name="cover" content="item-id"/>
+	for _, meta := range e.Metadata.Meta {
+		if meta.Name == "cover" {
+			if item := e.findItemByID(meta.Content); item != nil && isImageItem(item) {
+				return item
+			}
+		}
+	}
+
+	// EPUB 3: <item properties="cover-image"/>
+	for i := range e.Manifest {
+		if e.Manifest[i].HasProperty("cover-image") {
+			return &e.Manifest[i]
+		}
+	}
 
 	// Try common cover item IDs
 	coverIDs := []string{"cover", "cover-image", "cover-img"}
 	for _, id := range coverIDs {
 		item := e.findItemByID(id)
-		if item != nil && (strings.HasPrefix(item.MediaType, "image/") ||
-			strings.HasSuffix(strings.ToLower(item.Href), ".jpg") ||
-			strings.HasSuffix(strings.ToLower(item.Href), ".jpeg") ||
-			strings.HasSuffix(strings.ToLower(item.Href), ".png") ||
-			strings.HasSuffix(strings.ToLower(item.Href), ".gif")) {
+		if item != nil && isImageItem(item) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// isImageItem reports whether a manifest item is an image, judging by its
+// media type or file extension
+func isImageItem(item *Item) bool {
+	if strings.HasPrefix(item.MediaType, "image/") {
+		return true
+	}
+
+	switch strings.ToLower(filepath.Ext(item.Href)) {
+	case ".jpg", ".jpeg", ".png", ".gif":
+		return true
+	}
+	return false
+}
+
+// ReadingOrder returns the spine items that make up the primary reading order
+//
+// Spine items marked linear="no", such as footnotes or advertisements that
+// are only reached by following links, are excluded. Items without a linear
+// attribute are linear. With WithSkipCoverPage, the cover page is excluded
+// as well.
+func (e *Epub) ReadingOrder(opts ...Option) []ItemRef {
+	options := applyOptions(opts...)
+	cover := -1
+	if options.SkipCoverPage {
+		cover = e.coverPageIndex()
+	}
+
+	var refs []ItemRef
+	for i, itemRef := range e.Spine {
+		if itemRef.IsLinear() && i != cover {
+			refs = append(refs, itemRef)
+		}
+	}
+	return refs
+}
 
-			return e.GetFileReader(item.Href)
+// coverPageIndex returns the spine index of the cover page, or -1
+//
+// The cover page must be named by a guide reference or landmark of type
+// "cover" and be the first linear HTML document of the spine, so that a
+// mislabeled reference never drops a real chapter from the middle of the
+// book.
+func (e *Epub) coverPageIndex() int {
+	first := -1
+	for i, itemRef := range e.Spine {
+		if !itemRef.IsLinear() {
+			continue
+		}
+		if _, err := e.chapterItem(i); err == nil {
+			first = i
+			break
 		}
 	}
+	if first < 0 {
+		return -1
+	}
 
-	// If no cover found, return nil without error
-	return nil, nil
+	var hrefs []string
+	for _, ref := range e.Guide {
+		if strings.EqualFold(ref.Type, "cover") {
+			hrefs = append(hrefs, ref.Href)
+		}
+	}
+	for _, landmark := range e.landmarks {
+		if landmark.Type == "cover" {
+			hrefs = append(hrefs, landmark.Href)
+		}
+	}
+
+	for _, href := range hrefs {
+		if index, err := e.SpineIndexForHref(href); err == nil && index == first {
+			return first
+		}
+	}
+	return -1
+}
+
+// NonLinearItems returns the spine items marked linear="no"
+//
+// These items are supplementary content that is excluded from ReadingOrder
+// and, by default, from GetChapters.
+func (e *Epub) NonLinearItems() []ItemRef {
+	var refs []ItemRef
+	for _, itemRef := range e.Spine {
+		if !itemRef.IsLinear() {
+			refs = append(refs, itemRef)
+		}
+	}
+	return refs
+}
+
+// SpineItems returns the manifest items of the spine in reading order
+//
+// Both linear and non-linear spine items are included. Spine entries whose
+// idref does not match a manifest item are skipped; use SpineItemsErr to
+// find out about them.
+//
+// Example:
+//
+//	for _, item := range e.SpineItems() {
+//		fmt.Println(item.Href)
+//	}
+func (e *Epub) SpineItems() []Item {
+	items, _ := e.SpineItemsErr()
+	return items
+}
+
+// SpineItemsErr returns the manifest items of the spine in reading order
+// like SpineItems, together with an error for every spine entry whose idref
+// does not match a manifest item
+func (e *Epub) SpineItemsErr() ([]Item, []error) {
+	var items []Item
+	var errs []error
+	for i, itemRef := range e.Spine {
+		item := e.findItemByID(itemRef.IDRef)
+		if item == nil {
+			errs = append(errs, fmt.Errorf("spine item %d references unknown manifest item: %s", i, itemRef.IDRef))
+			continue
+		}
+		items = append(items, *item)
+	}
+	return items, errs
+}
+
+// ChapterCount returns the number of chapters in the book
+//
+// This method counts the linear spine items whose manifest item is an HTML
+// document, matching the chapters returned by GetChapters without options.
+// It only consults the parsed package document and never reads from the
+// archive.
+func (e *Epub) ChapterCount() int {
+	count := 0
+	for _, itemRef := range e.ReadingOrder() {
+		item := e.findItemByID(itemRef.IDRef)
+		if item != nil && strings.Contains(item.MediaType, "html") {
+			count++
+		}
+	}
+	return count
+}
+
+// ChapterSize returns the uncompressed size in bytes of a chapter
+//
+// The size is taken from the archive's central directory, so the chapter is
+// not read or decompressed. The index is zero-based, as for
+// GetChapterContent. This lets a caller decide whether a chapter is worth
+// loading before doing so.
+func (e *Epub) ChapterSize(chapterIndex int) (int64, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return 0, err
+	}
+	return e.itemSize(item)
+}
+
+// ChapterSizes returns the uncompressed size in bytes of every chapter
+//
+// The result has one entry per spine item, indexed like ChapterSize. Spine
+// items that are not HTML documents have a size of zero. As with ChapterSize,
+// no chapter is read or decompressed.
+//
+// Example:
+//
+//	sizes, err := e.ChapterSizes()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for i, size := range sizes {
+//		fmt.Printf("chapter %d: %d bytes\n", i, size)
+//	}
+func (e *Epub) ChapterSizes() ([]int64, error) {
+	sizes := make([]int64, len(e.Spine))
+	for i := range e.Spine {
+		item, err := e.chapterItem(i)
+		if errors.Is(err, ErrNotHTML) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if sizes[i], err = e.itemSize(item); err != nil {
+			return nil, err
+		}
+	}
+	return sizes, nil
+}
+
+// itemSize returns the uncompressed size of a manifest item's file without
+// reading it
+func (e *Epub) itemSize(item *Item) (int64, error) {
+	itemPath := e.resolveHref(item.Href)
+	name, ok := e.findFile(itemPath)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrFileNotFound, itemPath)
+	}
+
+	s, ok := e.src.(sizer)
+	if !ok {
+		return 0, fmt.Errorf("file sizes are not available for this source")
+	}
+	size, ok := s.size(name)
+	if !ok {
+		return 0, fmt.Errorf("failed to get size of %s", itemPath)
+	}
+	return size, nil
 }
 
 // GetChapters returns all chapter content
@@ -544,6 +1725,9 @@ func (e *Epub) GetCover() (io.ReadCloser, error) {
 // This method extracts all chapters from the EPUB file based on the spine order
 // defined in the package document. It only processes items with HTML media types
 // and attempts to extract chapter titles from the table of contents.
+// Spine items marked linear="no" are skipped unless WithIncludeNonLinear is
+// given. The Order of each chapter is its one-based spine position, unless
+// documents are split with WithSplitByHeading.
 //
 // The method returns a slice of Chapter structs containing the title, content,
 // and order of each chapter. If there are no chapters or an error occurs during
@@ -560,31 +1744,88 @@ func (e *Epub) GetCover() (io.ReadCloser, error) {
 //		fmt.Printf("Chapter %d: %s\n", chapter.Order, chapter.Title)
 //	}
 func (e *Epub) GetChapters(opts ...Option) ([]Chapter, error) {
-	options := applyOptions(opts...)
+	var chapters []Chapter
 
-	// Check if context is already cancelled
-	if err := options.checkContext(); err != nil {
-		return nil, err
+	for chapter, err := range e.ChaptersIter(opts...) {
+		if err != nil {
+			return nil, err
+		}
+		chapters = append(chapters, chapter)
 	}
 
-	var chapters []Chapter
+	return chapters, nil
+}
 
-	// Get chapters according to spine order
-	for i, itemRef := range e.Spine {
-		// Check for cancellation periodically
-		if i%5 == 0 && options.isCancelled() {
-			return nil, options.ctx.Err()
+// ChaptersIter returns an iterator over the chapters of the book
+//
+// The iterator yields the same chapters as GetChapters, in spine order and
+// honoring the same options, but reads each chapter from the archive only
+// when it is reached. This keeps memory usage bounded by the largest chapter
+// rather than the whole book. The context given with WithContext is checked
+// before every chapter; once it is cancelled, the iterator yields its error
+// and stops.
+//
+// Example:
+//
+//	for chapter, err := range e.ChaptersIter() {
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Printf("Chapter %d: %s\n", chapter.Order, chapter.Title)
+//	}
+func (e *Epub) ChaptersIter(opts ...Option) iter.Seq2[Chapter, error] {
+	return func(yield func(Chapter, error) bool) {
+		options := applyOptions(opts...)
+
+		// Check if context is already cancelled
+		if err := options.checkContext(); err != nil {
+			yield(Chapter{}, err)
+			return
 		}
 
-		item := e.findItemByID(itemRef.IDRef)
-		if item == nil {
-			continue
+		titles := e.tocTitles()
+
+		cover := -1
+		if options.SkipCoverPage {
+			cover = e.coverPageIndex()
+		}
+
+		// Collect the spine items that will be read up front, so that the
+		// total is known for progress reporting
+		var indexes []int
+		for i, itemRef := range e.Spine {
+			// Skip supplementary content unless requested
+			if !itemRef.IsLinear() && !options.IncludeNonLinear {
+				continue
+			}
+			if i == cover {
+				continue
+			}
+
+			// Only process HTML content files
+			item := e.findItemByID(itemRef.IDRef)
+			if item != nil && strings.Contains(item.MediaType, "html") {
+				indexes = append(indexes, i)
+			}
 		}
 
-		// Only process HTML content files
-		if strings.Contains(item.MediaType, "html") {
-			chapterPath := filepath.Join(filepath.Dir(e.RootFile), item.Href)
+		// order numbers the chapters when documents are split by heading
+		order := 0
+
+		// Get chapters according to spine order
+		for done, i := range indexes {
+			if options.isCancelled() {
+				yield(Chapter{}, options.ctx.Err())
+				return
+			}
+
+			item := e.findItemByID(e.Spine[i].IDRef)
+			chapterPath := e.resolveHref(item.Href)
 			content, err := e.getFile(chapterPath)
+
+			if options.Progress != nil {
+				options.Progress(done+1, len(indexes))
+			}
 			if err != nil {
 				continue
 			}
@@ -594,28 +1835,39 @@ func (e *Epub) GetChapters(opts ...Option) ([]Chapter, error) {
 				continue
 			}
 
-			// Extract chapter title (may need more complex parsing)
-			title := fmt.Sprintf("Chapter %d", i+1)
-			if e.TOC != nil && i < len(e.TOC.NavMap) {
-				title = e.TOC.NavMap[i].Label
+			if options.ForceUTF8 {
+				if content, err = toUTF8(content); err != nil {
+					continue
+				}
 			}
 
-			chapter := Chapter{
-				Title:   title,
+			chapters := []Chapter{{
+				Title:   e.chapterTitle(i, item, content, titles),
 				Content: string(content),
 				Order:   i + 1,
+				Href:    item.Href,
+				ID:      item.ID,
+			}}
+			if options.SplitByHeading != "" {
+				chapters = splitChapter(chapters[0], content, options.SplitByHeading)
+				for j := range chapters {
+					order++
+					chapters[j].Order = order
+				}
 			}
 
-			// Apply chapter filter if set
-			if options.FilterChapters != nil && !options.FilterChapters(chapter) {
-				continue
-			}
+			for _, chapter := range chapters {
+				// Apply chapter filter if set
+				if options.FilterChapters != nil && !options.FilterChapters(chapter) {
+					continue
+				}
 
-			chapters = append(chapters, chapter)
+				if !yield(chapter, nil) {
+					return
+				}
+			}
 		}
 	}
-
-	return chapters, nil
 }
 
 // GetChapterContent returns the content of a specific chapter
@@ -634,50 +1886,196 @@ func (e *Epub) GetChapters(opts ...Option) ([]Chapter, error) {
 //	}
 //	fmt.Println(content)
 func (e *Epub) GetChapterContent(chapterIndex int, opts ...Option) (string, error) {
+	content, err := e.GetChapterContentBytes(chapterIndex, opts...)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// GetChapterContentBytes returns the raw content of a specific chapter
+//
+// This method behaves like GetChapterContent but returns the bytes of the
+// chapter file without converting them to a string, avoiding a copy for
+// callers that hash, parse or otherwise transform the content. The returned
+// slice belongs to the caller.
+func (e *Epub) GetChapterContentBytes(chapterIndex int, opts ...Option) ([]byte, error) {
 	options := applyOptions(opts...)
 
 	// Check if context is already cancelled
 	if err := options.checkContext(); err != nil {
-		return "", err
+		return nil, err
+	}
+
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	chapterPath := e.resolveHref(item.Href)
+	content, err := e.getFile(chapterPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	// Apply content length filter if set
+	if options.MaxContentLength > 0 && int64(len(content)) > options.MaxContentLength {
+		return nil, fmt.Errorf("chapter content exceeds maximum length")
+	}
+
+	if options.ForceUTF8 {
+		return toUTF8(content)
+	}
+	return content, nil
+}
+
+// GetChapterByHref returns the chapter a href points to
+//
+// The href is interpreted relative to the package document directory, like
+// manifest hrefs and TOCEntry.Href, and any "#fragment" is ignored. The
+// returned Chapter is populated as by GetChapters, with its Order matching the
+// document's spine position. An error is returned if the href does not refer
+// to an HTML document in the spine.
+//
+// Example:
+//
+//	chapter, err := e.GetChapterByHref("text/chapter2.xhtml#section1")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(chapter.Title)
+func (e *Epub) GetChapterByHref(href string) (Chapter, error) {
+	index, err := e.SpineIndexForHref(href)
+	if err != nil {
+		return Chapter{}, err
+	}
+	return e.GetChapter(index)
+}
+
+// NextChapter returns the chapter following the document a href points to
+//
+// The href is interpreted as for GetChapterByHref, so any "#fragment" is
+// ignored. The next linear HTML document in spine order is returned, skipping
+// spine items marked linear="no" and items that are not HTML documents. ok is
+// false if the href points to the last chapter. An error is returned if the
+// href does not refer to a document in the spine.
+//
+// Example:
+//
+//	next, ok, err := e.NextChapter(current)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if ok {
+//		fmt.Println("Next:", next.Title)
+//	}
+func (e *Epub) NextChapter(href string) (Chapter, bool, error) {
+	return e.adjacentChapter(href, 1)
+}
+
+// PrevChapter returns the chapter preceding the document a href points to
+//
+// It behaves like NextChapter, searching backwards in spine order. ok is
+// false if the href points to the first chapter.
+func (e *Epub) PrevChapter(href string) (Chapter, bool, error) {
+	return e.adjacentChapter(href, -1)
+}
+
+// adjacentChapter returns the nearest linear HTML chapter in the given
+// direction from the spine position of href
+func (e *Epub) adjacentChapter(href string, step int) (Chapter, bool, error) {
+	index, err := e.SpineIndexForHref(href)
+	if err != nil {
+		return Chapter{}, false, err
+	}
+
+	for i := index + step; i >= 0 && i < len(e.Spine); i += step {
+		if !e.Spine[i].IsLinear() {
+			continue
+		}
+		if _, err := e.chapterItem(i); err != nil {
+			continue
+		}
+
+		chapter, err := e.GetChapter(i)
+		if err != nil {
+			return Chapter{}, false, err
+		}
+		return chapter, true, nil
+	}
+
+	return Chapter{}, false, nil
+}
+
+// GetChapter returns a single chapter with all of its fields populated
+//
+// The chapter at the given zero-based spine index is read on its own, without
+// loading the rest of the book, and populated as by GetChapters: its Title is
+// resolved from the table of contents or the document's <title>, its Order
+// is its one-based spine position, and its Href and ID identify the
+// document's manifest item. Options such as WithMaxContentLength and
+// WithForceUTF8 are applied as for GetChapterContent. Unlike GetChapters,
+// non-linear spine items can be read.
+//
+// Example:
+//
+//	chapter, err := e.GetChapter(2)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%d. %s (%s)\n", chapter.Order, chapter.Title, chapter.Href)
+func (e *Epub) GetChapter(chapterIndex int, opts ...Option) (Chapter, error) {
+	content, err := e.GetChapterContentBytes(chapterIndex, opts...)
+	if err != nil {
+		return Chapter{}, err
 	}
 
+	item := e.findItemByID(e.Spine[chapterIndex].IDRef)
+	return Chapter{
+		Title:   e.chapterTitle(chapterIndex, item, content, e.tocTitles()),
+		Content: string(content),
+		Order:   chapterIndex + 1,
+		Href:    item.Href,
+		ID:      item.ID,
+	}, nil
+}
+
+// chapterItem returns the manifest item of the chapter at the given spine index
+//
+// An error is returned if the index is out of range, the spine item has no
+// matching manifest item, or the item is not an HTML document.
+func (e *Epub) chapterItem(chapterIndex int) (*Item, error) {
 	// Validate chapter index by checking spine
 	if chapterIndex < 0 || chapterIndex >= len(e.Spine) {
-		return "", fmt.Errorf("chapter index out of range")
+		return nil, fmt.Errorf("%w: %d", ErrChapterOutOfRange, chapterIndex)
 	}
 
 	itemRef := e.Spine[chapterIndex]
 	item := e.findItemByID(itemRef.IDRef)
 	if item == nil {
-		return "", fmt.Errorf("chapter item not found")
+		return nil, fmt.Errorf("chapter item not found")
 	}
 
 	// Only process HTML content files
 	if !strings.Contains(item.MediaType, "html") {
-		return "", fmt.Errorf("chapter is not an HTML document")
-	}
-
-	chapterPath := filepath.Join(filepath.Dir(e.RootFile), item.Href)
-	content, err := e.getFile(chapterPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get chapter content: %w", err)
-	}
-
-	// Apply content length filter if set
-	if options.MaxContentLength > 0 && int64(len(content)) > options.MaxContentLength {
-		return "", fmt.Errorf("chapter content exceeds maximum length")
+		return nil, fmt.Errorf("chapter %d is %w", chapterIndex, ErrNotHTML)
 	}
 
-	return string(content), nil
+	return item, nil
 }
 
-// GetChapterReader returns an io.Reader for a specific chapter
+// GetChapterReader returns an io.ReadCloser for a specific chapter
 //
-// This method returns an io.Reader for the content of a chapter at the specified index.
+// This method returns an io.ReadCloser for the content of a chapter at the specified index.
 // The index is zero-based, so the first chapter is at index 0.
 //
-// This is useful when you want to stream the chapter content rather than load it
-// entirely into memory. The returned reader can be used with standard Go io operations.
+// The content is streamed directly from the EPUB archive rather than loaded
+// entirely into memory. The caller is responsible for closing the returned
+// reader when finished with it.
+//
+// If WithMaxContentLength is given, chapters whose uncompressed size exceeds
+// the limit are rejected with an error, and reading past the limit fails
+// with ErrFileTooLarge, so a truncated chapter is never returned as whole.
 //
 // If the chapter index is out of range or an error occurs while retrieving the
 // chapter content, an error is returned.
@@ -688,19 +2086,87 @@ func (e *Epub) GetChapterContent(chapterIndex int, opts ...Option) (string, erro
 //	if err != nil {
 //		log.Fatal(err)
 //	}
+//	defer reader.Close()
 //
 //	// Copy the content to stdout
 //	_, err = io.Copy(os.Stdout, reader)
 //	if err != nil {
 //		log.Fatal(err)
 //	}
-func (e *Epub) GetChapterReader(chapterIndex int, opts ...Option) (io.Reader, error) {
-	content, err := e.GetChapterContent(chapterIndex, opts...)
+func (e *Epub) GetChapterReader(chapterIndex int, opts ...Option) (io.ReadCloser, error) {
+	options := applyOptions(opts...)
+
+	// Check if context is already cancelled
+	if err := options.checkContext(); err != nil {
+		return nil, err
+	}
+
+	item, err := e.chapterItem(chapterIndex)
 	if err != nil {
 		return nil, err
 	}
 
-	return strings.NewReader(content), nil
+	chapterPath := e.resolveHref(item.Href)
+	name, ok := e.findFile(chapterPath)
+	if !ok {
+		return nil, fmt.Errorf("failed to get chapter content: %w: %s", ErrFileNotFound, chapterPath)
+	}
+
+	// Apply content length filter if set
+	maxLen := options.MaxContentLength
+	if s, ok := e.src.(sizer); ok && maxLen > 0 {
+		if size, ok := s.size(name); ok && size > maxLen {
+			return nil, fmt.Errorf("chapter content exceeds maximum length")
+		}
+	}
+
+	rc, err := e.src.open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	if maxLen > 0 {
+		// Guard against entries whose header understates their size
+		return readCloser{Reader: &limitedReader{r: rc, n: maxLen}, Closer: rc}, nil
+	}
+	return rc, nil
+}
+
+// WriteChapterContent copies the content of a specific chapter to w
+//
+// The chapter is streamed from the archive as by GetChapterReader, without
+// buffering it in memory, and the number of bytes written is returned. This
+// suits io.Writer based pipelines such as HTTP handlers. WithMaxContentLength
+// applies as for GetChapterReader. The context given with WithContext is
+// checked throughout the copy, which stops with the context's error once it
+// is cancelled.
+//
+// Example:
+//
+//	func serveChapter(w http.ResponseWriter, r *http.Request) {
+//		w.Header().Set("Content-Type", "application/xhtml+xml")
+//		if _, err := book.WriteChapterContent(0, w, epub.WithContext(r.Context())); err != nil {
+//			log.Print(err)
+//		}
+//	}
+func (e *Epub) WriteChapterContent(chapterIndex int, w io.Writer, opts ...Option) (int64, error) {
+	rc, err := e.GetChapterReader(chapterIndex, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	n, err := io.Copy(w, contextReader{ctx: applyOptions(opts...).ctx, r: rc})
+	if err != nil {
+		return n, fmt.Errorf("failed to write chapter content: %w", err)
+	}
+	return n, nil
+}
+
+// readCloser combines a Reader with the Closer of the underlying source
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
 
 // GetFileReader returns an io.Reader for a file in the EPUB by path
@@ -712,6 +2178,10 @@ func (e *Epub) GetChapterReader(chapterIndex int, opts ...Option) (io.Reader, er
 // images, or other resources. The caller is responsible for closing the returned
 // ReadCloser when finished with it.
 //
+// Fonts obfuscated with the IDPF or Adobe algorithm, as declared in
+// META-INF/encryption.xml, are transparently de-obfuscated. Use IsObfuscated
+// to detect such files.
+//
 // If the specified file is not found in the EPUB, an error is returned.
 //
 // Example:
@@ -728,15 +2198,96 @@ func (e *Epub) GetChapterReader(chapterIndex int, opts ...Option) (io.Reader, er
 //	}
 //	fmt.Println(string(content))
 func (e *Epub) GetFileReader(path string) (io.ReadCloser, error) {
-	path = filepath.ToSlash(path)
+	name, ok := e.findFile(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, filepath.ToSlash(path))
+	}
+	return e.openEntry(name)
+}
 
-	for _, file := range e.File.File {
-		if filepath.ToSlash(file.Name) == path {
-			return file.Open()
-		}
+// openEntry opens the archive entry with the exact given name for reading,
+// applying the size limit given with WithMaxContentLength and restoring
+// obfuscated fonts
+func (e *Epub) openEntry(name string) (io.ReadCloser, error) {
+	if err := e.checkSize(name, e.maxFileSize); err != nil {
+		return nil, err
+	}
+
+	rc, err := e.src.open(name)
+	if err != nil {
+		return nil, err
+	}
+	if e.maxFileSize > 0 {
+		// Guard against entries whose header understates their size
+		rc = readCloser{Reader: &limitedReader{r: rc, n: e.maxFileSize}, Closer: rc}
+	}
+
+	// Restore fonts obfuscated as described in encryption.xml
+	return e.deobfuscate(name, rc)
+}
+
+// GetItemReader returns an io.ReadCloser for a manifest item by its ID
+//
+// This method looks up the item with the given ID in the manifest, resolves
+// its href relative to the package document, and opens it as GetFileReader
+// does. The caller is responsible for closing the returned ReadCloser.
+//
+// Example:
+//
+//	reader, err := e.GetItemReader(e.Spine[0].IDRef)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer reader.Close()
+func (e *Epub) GetItemReader(id string) (io.ReadCloser, error) {
+	item := e.findItemByID(id)
+	if item == nil {
+		return nil, fmt.Errorf("manifest item not found: %s", id)
 	}
 
-	return nil, fmt.Errorf("file not found: %s", path)
+	return e.GetFileReader(e.resolveHref(item.Href))
+}
+
+// RawPackage returns the bytes of the package document
+//
+// The package document (.opf file) at RootFile is returned exactly as stored
+// in the EPUB, which is useful for tools that diff or re-serialize it.
+func (e *Epub) RawPackage() ([]byte, error) {
+	return e.getFile(e.RootFile)
+}
+
+// RawNCX returns the bytes of the EPUB 2 NCX file
+//
+// The NCX is located via the spine's toc attribute or its media type, as
+// when the table of contents is parsed, and returned exactly as stored in
+// the EPUB. ErrNoTOC is returned if the book has no NCX, which is common for
+// EPUB 3 books that only ship a navigation document.
+func (e *Epub) RawNCX() ([]byte, error) {
+	item := e.findNCXItem()
+	if item == nil {
+		return nil, ErrNoTOC
+	}
+	return e.getFile(e.resolveHref(item.Href))
+}
+
+// GetNavHTML returns the XHTML of the EPUB 3 navigation document
+//
+// The navigation document is the manifest item with the "nav" property. Its
+// markup is returned exactly as stored in the EPUB, for apps that render the
+// publisher's styled contents page instead of the structured GetTOC.
+// ErrNoTOC is returned if the book has no navigation document, as is the
+// case for EPUB 2 books.
+func (e *Epub) GetNavHTML() (string, error) {
+	item := e.findNavItem()
+	if item == nil {
+		return "", ErrNoTOC
+	}
+
+	data, err := e.getFile(e.resolveHref(item.Href))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 // Close closes the EPUB file
@@ -744,6 +2295,11 @@ func (e *Epub) GetFileReader(path string) (io.ReadCloser, error) {
 // This method closes the underlying EPUB file and releases any associated resources.
 // It should be called when finished working with the EPUB to prevent resource leaks.
 //
+// Close is safe to call more than once; calls after the first do nothing and
+// return nil. It is also safe to call on an Epub created with New and the
+// other constructors that do not own an underlying file, for which it only
+// releases the cache.
+//
 // Example:
 //
 //	e, err := epub.Open("book.epub")
@@ -752,8 +2308,13 @@ func (e *Epub) GetFileReader(path string) (io.ReadCloser, error) {
 //	}
 //	defer e.Close() // Ensures the file is closed when done
 func (e *Epub) Close() error {
+	if e.cache != nil {
+		e.cache.clear()
+	}
 	if e.readCloser != nil {
-		return e.readCloser.Close()
+		rc := e.readCloser
+		e.readCloser = nil
+		return rc.Close()
 	}
 	return nil
 }
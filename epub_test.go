@@ -1,12 +1,22 @@
 package epub
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 func getTestEpubPath() string {
@@ -14,6 +24,57 @@ func getTestEpubPath() string {
 	return filepath.Join(filepath.Dir(filename), ".", "testdata", "test.epub")
 }
 
+// testContainerXML is a container.xml pointing at OEBPS/content.opf
+const testContainerXML = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+	<rootfiles>
+		<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+	</rootfiles>
+</container>`
+
+// buildTestZip builds an in-memory EPUB archive from the given files.
+// The mimetype entry is always written first and uncompressed.
+func buildTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	mw, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatalf("Failed to create mimetype entry: %v", err)
+	}
+	io.WriteString(mw, "application/epub+zip")
+
+	if _, ok := files["META-INF/container.xml"]; !ok {
+		files["META-INF/container.xml"] = testContainerXML
+	}
+
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry %s: %v", name, err)
+		}
+		io.WriteString(fw, content)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newTestEpub parses an in-memory EPUB built from the given files
+func newTestEpub(t *testing.T, files map[string]string) *Epub {
+	t.Helper()
+
+	epub, err := OpenBytes(buildTestZip(t, files))
+	if err != nil {
+		t.Fatalf("Failed to parse test EPUB: %v", err)
+	}
+	return epub
+}
+
 func TestOpen(t *testing.T) {
 	epub, err := Open(getTestEpubPath())
 	if err != nil {
@@ -26,6 +87,114 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenContext(t *testing.T) {
+	epub, err := OpenContext(context.Background(), getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if epub.GetTitle() == "" {
+		t.Error("Expected title to be non-empty")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := OpenContext(ctx, getTestEpubPath()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	reader, err := zip.OpenReader(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+	defer reader.Close()
+
+	if _, err := NewContext(ctx, &reader.Reader); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from NewContext, got %v", err)
+	}
+}
+
+func TestOpenWithTimeout(t *testing.T) {
+	epub, err := OpenWithTimeout(getTestEpubPath(), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if epub.GetTitle() == "" {
+		t.Error("Expected title to be non-empty")
+	}
+	if _, err := epub.GetChapters(); err != nil {
+		t.Errorf("Expected the Epub to remain usable after parsing, got %v", err)
+	}
+
+	if _, err := OpenWithTimeout(getTestEpubPath(), 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := contextReader{ctx: ctx, r: strings.NewReader("data")}
+	if _, err := r.Read(make([]byte, 4)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected reads to fail once the context is done, got %v", err)
+	}
+}
+
+func TestOpen_ParseFileSizeLimit(t *testing.T) {
+	defer func(limit int64) { maxParseFileSize = limit }(maxParseFileSize)
+
+	files := testEPUB3Files()
+	maxParseFileSize = int64(len(files["OEBPS/content.opf"]) + 1)
+	files["OEBPS/nav.xhtml"] += strings.Repeat(" ", int(maxParseFileSize))
+	files["OEBPS/toc.ncx"] += strings.Repeat(" ", int(maxParseFileSize))
+
+	_, err := OpenBytes(buildTestZip(t, files))
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge for an oversized NCX, got %v", err)
+	}
+
+	data, err := readAllLimited(strings.NewReader("12345"), 4)
+	if !errors.Is(err, ErrFileTooLarge) || data != nil {
+		t.Errorf("Expected ErrFileTooLarge past the limit, got %q %v", data, err)
+	}
+	if data, err := readAllLimited(strings.NewReader("1234"), 4); err != nil || string(data) != "1234" {
+		t.Errorf("Expected content within the limit, got %q %v", data, err)
+	}
+}
+
+func TestOpen_WithMaxContentLength(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/images/big.png"] = strings.Repeat("x", 64<<10)
+	limit := int64(32 << 10)
+
+	epub, err := OpenBytes(buildTestZip(t, files), WithMaxContentLength(limit))
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	if _, err := epub.GetFileReader("OEBPS/images/big.png"); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge from GetFileReader, got %v", err)
+	}
+	if _, err := epub.getFile("OEBPS/images/big.png"); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge from getFile, got %v", err)
+	}
+
+	// Entries whose header understates their size are cut off while reading
+	r := &limitedReader{r: strings.NewReader(files["OEBPS/images/big.png"]), n: limit}
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge while reading past the limit, got %v", err)
+	}
+	r = &limitedReader{r: strings.NewReader("1234"), n: 4}
+	if data, err := io.ReadAll(r); err != nil || string(data) != "1234" {
+		t.Errorf("Expected content within the limit, got %q %v", data, err)
+	}
+
+	if _, err := OpenBytes(buildTestZip(t, files), WithMaxContentLength(16)); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge for metadata files past the limit, got %v", err)
+	}
+}
+
 func TestEpub_GetTitle(t *testing.T) {
 	epub, err := Open(getTestEpubPath())
 	if err != nil {
@@ -59,6 +228,34 @@ func TestEpub_GetDescription(t *testing.T) {
 	println(description)
 }
 
+func TestEpub_GetVersion(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if version := epub.GetVersion(); version != "2.0" {
+		t.Errorf("Expected version 2.0, got %q", version)
+	}
+
+	if epub.IsEPUB3() {
+		t.Error("Expected EPUB 2 book not to be reported as EPUB 3")
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, ` version="3.0"`, "", 1)
+	unversioned := newTestEpub(t, files)
+
+	if version := unversioned.GetVersion(); version != "" {
+		t.Errorf("Expected empty version when attribute is missing, got %q", version)
+	}
+
+	if !newTestEpub(t, testEPUB3Files()).IsEPUB3() {
+		t.Error("Expected version 3.0 book to be reported as EPUB 3")
+	}
+}
+
 func TestEpub_GetChapters(t *testing.T) {
 	epub, err := Open(getTestEpubPath())
 	if err != nil {
@@ -78,6 +275,188 @@ func TestEpub_GetChapters(t *testing.T) {
 	if chapters[1].Order != 2 {
 		t.Errorf("Expected second chapter order to be 2, got %d", chapters[1].Order)
 	}
+
+	for _, chapter := range chapters {
+		itemRef := epub.Spine[chapter.Order-1]
+		item := epub.findItemByID(itemRef.IDRef)
+		if chapter.ID != itemRef.IDRef || chapter.Href != item.Href {
+			t.Errorf("Chapter %d: expected ID %q and href %q, got %q and %q", chapter.Order, itemRef.IDRef, item.Href, chapter.ID, chapter.Href)
+		}
+	}
+}
+
+func TestEpub_Close_Twice(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+
+	if err := epub.Close(); err != nil {
+		t.Fatalf("Failed to close EPUB: %v", err)
+	}
+	if err := epub.Close(); err != nil {
+		t.Errorf("Expected second Close to return nil, got %v", err)
+	}
+
+	data, err := os.ReadFile(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to read test EPUB: %v", err)
+	}
+	epub, err = OpenBytes(data)
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := epub.Close(); err != nil {
+			t.Errorf("Expected Close without an owned file to return nil, got %v", err)
+		}
+	}
+}
+
+func TestEpub_ItemsWithProperty(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `href="text/two.xhtml" media-type="application/xhtml+xml"`,
+		`href="text/two.xhtml" media-type="application/xhtml+xml" properties="scripted  mathml"`, 1)
+	epub := newTestEpub(t, files)
+
+	nav := epub.ItemsWithProperty("nav")
+	if len(nav) != 1 || nav[0].ID != "nav" {
+		t.Errorf("Expected the nav item, got %+v", nav)
+	}
+
+	for _, prop := range []string{"scripted", "mathml"} {
+		items := epub.ItemsWithProperty(prop)
+		if len(items) != 1 || items[0].ID != "c2" {
+			t.Errorf("Expected item c2 for %s, got %+v", prop, items)
+		}
+	}
+
+	if items := epub.ItemsWithProperty("math"); items != nil {
+		t.Errorf("Expected property names to match exactly, got %+v", items)
+	}
+}
+
+func TestEpub_ChapterCount(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	if count := epub.ChapterCount(); count != len(chapters) {
+		t.Errorf("Expected %d chapters, got %d", len(chapters), count)
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `<itemref idref="c2"/>`, `<itemref idref="c2" linear="no"/>
+		<itemref idref="ncx"/>`, 1)
+	if count := newTestEpub(t, files).ChapterCount(); count != 1 {
+		t.Errorf("Expected non-linear and non-HTML items to be excluded, got %d", count)
+	}
+}
+
+func TestEpub_ChapterSizes(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `<itemref idref="c2"/>`, `<itemref idref="c2"/>
+		<itemref idref="ncx"/>`, 1)
+	files["OEBPS/text/two.xhtml"] = `<html><body><p>Two</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	sizes, err := epub.ChapterSizes()
+	if err != nil {
+		t.Fatalf("Failed to get chapter sizes: %v", err)
+	}
+
+	want := []int64{int64(len(files["OEBPS/text/one.xhtml"])), int64(len(files["OEBPS/text/two.xhtml"])), 0}
+	if !reflect.DeepEqual(sizes, want) {
+		t.Errorf("Expected sizes %v, got %v", want, sizes)
+	}
+
+	size, err := epub.ChapterSize(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter size: %v", err)
+	}
+	if size != want[1] {
+		t.Errorf("Expected size %d, got %d", want[1], size)
+	}
+
+	if _, err := epub.ChapterSize(2); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("Expected ErrNotHTML, got %v", err)
+	}
+	if _, err := epub.ChapterSize(99); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
+
+func TestEpub_ChaptersIter(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	i := 0
+	for chapter, err := range epub.ChaptersIter() {
+		if err != nil {
+			t.Fatalf("Unexpected error from iterator: %v", err)
+		}
+		if chapter.Order != chapters[i].Order || chapter.Title != chapters[i].Title {
+			t.Errorf("Chapter %d: got %d %q, want %d %q", i, chapter.Order, chapter.Title, chapters[i].Order, chapters[i].Title)
+		}
+		i++
+		if i == 2 {
+			break
+		}
+	}
+	if i != 2 {
+		t.Errorf("Expected to stop after 2 chapters, got %d", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var iterErr error
+	count := 0
+	for _, err := range epub.ChaptersIter(WithContext(ctx)) {
+		if err != nil {
+			iterErr = err
+			break
+		}
+		count++
+		cancel()
+	}
+	if count != 1 || !errors.Is(iterErr, context.Canceled) {
+		t.Errorf("Expected iteration to stop after cancellation, got %d chapters and %v", count, iterErr)
+	}
+}
+
+func TestEpub_GetChapters_WithProgress(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `<itemref idref="c2"/>`, `<itemref idref="c2"/>
+		<itemref idref="ncx"/>`, 1)
+	epub := newTestEpub(t, files)
+
+	var calls [][2]int
+	chapters, err := epub.GetChapters(WithProgress(func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}))
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	want := [][2]int{{1, 2}, {2, 2}}
+	if len(chapters) != 2 || !reflect.DeepEqual(calls, want) {
+		t.Errorf("Unexpected progress calls: got %v, want %v", calls, want)
+	}
 }
 
 func TestEpub_GetChapterContent(t *testing.T) {
@@ -103,6 +482,25 @@ func TestEpub_GetChapterContent(t *testing.T) {
 	}
 }
 
+func TestEpub_GetChapterContentBytes(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	content, err := epub.GetChapterContentBytes(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter content bytes: %v", err)
+	}
+	if string(content) != testChapterXHTML {
+		t.Errorf("Unexpected chapter content: %q", content)
+	}
+
+	if _, err := epub.GetChapterContentBytes(0, WithMaxContentLength(10)); err == nil {
+		t.Error("Expected error for chapter exceeding maximum length, got nil")
+	}
+	if _, err := epub.GetChapterContentBytes(5); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
+
 func TestEpub_GetChapterReader(t *testing.T) {
 	epub, err := Open(getTestEpubPath())
 	if err != nil {
@@ -114,6 +512,7 @@ func TestEpub_GetChapterReader(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to get chapter reader: %v", err)
 	}
+	defer reader.Close()
 
 	if reader == nil {
 		t.Error("Expected reader, got nil")
@@ -129,6 +528,21 @@ func TestEpub_GetChapterReader(t *testing.T) {
 		t.Error("Expected content from reader, got empty")
 	}
 
+	expected, err := epub.GetChapterContent(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+
+	if string(content) != expected {
+		t.Error("Expected reader content to match GetChapterContent")
+	}
+
+	// Test content length limit
+	_, err = epub.GetChapterReader(0, WithMaxContentLength(10))
+	if err == nil {
+		t.Error("Expected error for chapter exceeding maximum length, got nil")
+	}
+
 	// Test invalid chapter index
 	_, err = epub.GetChapterReader(10000)
 	if err == nil {
@@ -136,6 +550,94 @@ func TestEpub_GetChapterReader(t *testing.T) {
 	}
 }
 
+// understatedFS is an fs.FS whose Stat reports every file as one byte long
+type understatedFS struct {
+	fstest.MapFS
+}
+
+func (fsys understatedFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := fsys.MapFS.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	return understatedInfo{info}, nil
+}
+
+type understatedInfo struct {
+	fs.FileInfo
+}
+
+func (understatedInfo) Size() int64 { return 1 }
+
+func TestEpub_GetChapterReader_UnderstatedSize(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mimetype":               {Data: []byte("application/epub+zip")},
+		"META-INF/container.xml": {Data: []byte(testContainerXML)},
+	}
+	for name, content := range testEPUB3Files() {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	epub, err := OpenFS(understatedFS{fsys})
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+
+	reader, err := epub.GetChapterReader(0, WithMaxContentLength(10))
+	if err != nil {
+		t.Fatalf("Failed to get chapter reader: %v", err)
+	}
+	defer reader.Close()
+	if _, err := io.ReadAll(reader); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Expected ErrFileTooLarge for a truncated chapter, got %v", err)
+	}
+}
+
+// cancelWriter cancels a context on its first write
+type cancelWriter struct {
+	cancel context.CancelFunc
+	n      int
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	w.n += len(p)
+	return len(p), nil
+}
+
+func TestEpub_WriteChapterContent(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/two.xhtml"] = "<html><body>" + strings.Repeat("<p>Paragraph</p>", 20000) + "</body></html>"
+	epub := newTestEpub(t, files)
+
+	var buf bytes.Buffer
+	n, err := epub.WriteChapterContent(0, &buf)
+	if err != nil {
+		t.Fatalf("Failed to write chapter content: %v", err)
+	}
+	if buf.String() != testChapterXHTML || n != int64(len(testChapterXHTML)) {
+		t.Errorf("Unexpected content %q (%d bytes)", buf.String(), n)
+	}
+
+	if _, err := epub.WriteChapterContent(0, io.Discard, WithMaxContentLength(10)); err == nil {
+		t.Error("Expected error for chapter exceeding maximum length, got nil")
+	}
+
+	if _, err := epub.WriteChapterContent(99, io.Discard); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancelWriter{cancel: cancel}
+	n, err = epub.WriteChapterContent(1, w, WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if n != int64(w.n) || n >= int64(len(files["OEBPS/text/two.xhtml"])) {
+		t.Errorf("Expected the copy to stop early, wrote %d bytes", n)
+	}
+}
+
 func TestEpub_GetFileReader(t *testing.T) {
 	epub, err := Open(getTestEpubPath())
 	if err != nil {
@@ -225,3 +727,597 @@ func TestNewReader(t *testing.T) {
 		t.Error("Expected chapter content to be non-empty")
 	}
 }
+
+func TestEpub_GetGuideReference(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if len(epub.Guide) != 1 {
+		t.Fatalf("Expected 1 guide reference, got %d", len(epub.Guide))
+	}
+
+	ref, err := epub.GetGuideReference("封面")
+	if err != nil {
+		t.Fatalf("Failed to get guide reference: %v", err)
+	}
+
+	if ref.Href != "cover.html" {
+		t.Errorf("Expected guide href cover.html, got %q", ref.Href)
+	}
+
+	if _, err := epub.GetGuideReference("text"); err == nil {
+		t.Error("Expected error for missing guide reference, got nil")
+	}
+}
+
+func TestOpenBytes(t *testing.T) {
+	data, err := os.ReadFile(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to read test EPUB file: %v", err)
+	}
+
+	epub, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("Failed to parse EPUB with OpenBytes: %v", err)
+	}
+
+	if epub.GetTitle() == "" {
+		t.Error("Expected title to be non-empty")
+	}
+
+	if err := epub.Close(); err != nil {
+		t.Errorf("Expected Close to succeed, got %v", err)
+	}
+
+	if _, err := OpenBytes([]byte("not a zip")); err == nil {
+		t.Error("Expected error for invalid data, got nil")
+	}
+}
+
+func TestOpenReader(t *testing.T) {
+	file, err := os.Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open test EPUB file: %v", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		t.Fatalf("Failed to stat test EPUB file: %v", err)
+	}
+
+	epub, err := OpenReader(file, stat.Size())
+	if err != nil {
+		t.Fatalf("Failed to parse EPUB with OpenReader: %v", err)
+	}
+	defer epub.Close()
+
+	if _, err := epub.GetChapterContent(1); err != nil {
+		t.Errorf("Failed to get chapter content: %v", err)
+	}
+}
+
+func TestEpub_ConcurrentChapterAccess(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	want := make([]string, 8)
+	for i := range want {
+		want[i], err = epub.GetChapterContent(i)
+		if err != nil {
+			t.Fatalf("Failed to get chapter content: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range want {
+				content, err := epub.GetChapterContent(i)
+				if err != nil {
+					t.Errorf("Failed to get chapter content: %v", err)
+					return
+				}
+				if content != want[i] {
+					t.Errorf("Unexpected content for chapter %d", i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEpub_FindItemReturnsManifestElement(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	first := epub.findItemByID("c1")
+	second := epub.findItemByID("c2")
+	if first == nil || second == nil {
+		t.Fatal("Expected manifest items c1 and c2 to be found")
+	}
+
+	if first == second {
+		t.Error("Expected distinct pointers for distinct items")
+	}
+
+	if first.ID != "c1" || second.ID != "c2" {
+		t.Errorf("Retained pointers changed: got %q and %q", first.ID, second.ID)
+	}
+
+	if byHref := epub.findItemByHref("text/two.xhtml"); byHref != second {
+		t.Error("Expected findItemByHref to return the same manifest element as findItemByID")
+	}
+
+	if byType := epub.findItemByMediaType("application/x-dtbncx+xml"); byType != &epub.Manifest[1] {
+		t.Error("Expected findItemByMediaType to point into the manifest")
+	}
+}
+
+func TestEpub_ReadingOrder(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `<itemref idref="c1"/>`, `<itemref idref="c1" linear="no"/>`, 1)
+	epub := newTestEpub(t, files)
+
+	order := epub.ReadingOrder()
+	if len(order) != 1 || order[0].IDRef != "c2" {
+		t.Fatalf("Expected reading order [c2], got %+v", order)
+	}
+
+	nonLinear := epub.NonLinearItems()
+	if len(nonLinear) != 1 || nonLinear[0].IDRef != "c1" {
+		t.Fatalf("Expected non-linear items [c1], got %+v", nonLinear)
+	}
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if len(chapters) != 1 || chapters[0].Order != 2 {
+		t.Errorf("Expected only the linear chapter with order 2, got %+v", chapters)
+	}
+
+	chapters, err = epub.GetChapters(WithIncludeNonLinear())
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if len(chapters) != 2 {
+		t.Errorf("Expected 2 chapters with WithIncludeNonLinear, got %d", len(chapters))
+	}
+}
+
+func TestWithSkipCoverPage(t *testing.T) {
+	withCover := func(guideHref, landmarkHref string) map[string]string {
+		files := testEPUB3Files()
+		opf := strings.NewReplacer(
+			`<item id="c1"`, `<item id="cover" href="text/cover.xhtml" media-type="application/xhtml+xml"/>
+		<item id="c1"`,
+			`<itemref idref="c1"/>`, `<itemref idref="cover"/><itemref idref="c1"/>`,
+		).Replace(testNavOPF)
+		if guideHref != "" {
+			opf = strings.Replace(opf, "</package>", `<guide><reference type="cover" href="`+guideHref+`"/></guide></package>`, 1)
+		}
+		files["OEBPS/content.opf"] = opf
+		if landmarkHref != "" {
+			files["OEBPS/nav.xhtml"] = strings.Replace(testNavXHTML, `<a epub:type="bodymatter" href="text/one.xhtml">Start</a>`,
+				`<a epub:type="cover" href="`+landmarkHref+`">Cover</a>`, 1)
+		}
+		files["OEBPS/text/cover.xhtml"] = `<html><body><img src="../images/cover.jpg"/></body></html>`
+		return files
+	}
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantFirst string
+	}{
+		{"guide", withCover("text/cover.xhtml", ""), "c1"},
+		{"landmark", withCover("", "text/cover.xhtml"), "c1"},
+		{"no reference", withCover("", ""), "cover"},
+		{"reference to a later chapter", withCover("text/one.xhtml", ""), "cover"},
+	}
+	for _, tt := range tests {
+		epub := newTestEpub(t, tt.files)
+
+		order := epub.ReadingOrder(WithSkipCoverPage())
+		if len(order) == 0 || order[0].IDRef != tt.wantFirst {
+			t.Errorf("%s: expected reading order to start with %s, got %+v", tt.name, tt.wantFirst, order)
+		}
+		if len(epub.ReadingOrder()) != 3 {
+			t.Errorf("%s: expected the cover page without the option", tt.name)
+		}
+
+		chapters, err := epub.GetChapters(WithSkipCoverPage())
+		if err != nil {
+			t.Fatalf("%s: failed to get chapters: %v", tt.name, err)
+		}
+		wantChapters := 2
+		if tt.wantFirst == "cover" {
+			wantChapters = 3
+		}
+		if len(chapters) != wantChapters {
+			t.Errorf("%s: expected %d chapters, got %d", tt.name, wantChapters, len(chapters))
+		}
+	}
+}
+
+func TestWithSplitByHeading(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html><head><title>Omnibus</title></head><body>
+<p>Foreword</p>
+<h1>First  <em>Story</em></h1><p>A</p>
+<h2>Scene</h2><p>B</p>
+<h1>Second Story</h1><p>C</p>
+</body></html>`
+	epub := newTestEpub(t, files)
+
+	chapters, err := epub.GetChapters(WithSplitByHeading("h1"))
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	wantTitles := []string{"Part One", "First Story", "Second Story", "Part Two"}
+	if len(chapters) != len(wantTitles) {
+		t.Fatalf("Expected %d chapters, got %d", len(wantTitles), len(chapters))
+	}
+	wantContents := []string{
+		"<html><head><title>Omnibus</title></head><body>\n<p>Foreword</p>\n</body></html>",
+		"<html><body><h1>First  <em>Story</em></h1><p>A</p>\n<h2>Scene</h2><p>B</p>\n</body></html>",
+		"<html><body><h1>Second Story</h1><p>C</p>\n</body></html>",
+	}
+	for i, chapter := range chapters {
+		if chapter.Title != wantTitles[i] {
+			t.Errorf("Chapter %d: expected title %q, got %q", i, wantTitles[i], chapter.Title)
+		}
+		if chapter.Order != i+1 {
+			t.Errorf("Chapter %d: expected order %d, got %d", i, i+1, chapter.Order)
+		}
+		if i < len(wantContents) && chapter.Content != wantContents[i] {
+			t.Errorf("Chapter %d: expected content %q, got %q", i, wantContents[i], chapter.Content)
+		}
+	}
+
+	// Elements enclosing the headings are reopened and closed in every part
+	files["OEBPS/text/one.xhtml"] = `<html><body><section><h1>One</h1><p>A</p><h1>Two</h1><p>B</p></section></body></html>`
+	chapters, err = newTestEpub(t, files).GetChapters(WithSplitByHeading("h1"))
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if len(chapters) != 3 {
+		t.Fatalf("Expected 3 chapters, got %d", len(chapters))
+	}
+	if want := "<html><body><section><h1>One</h1><p>A</p></section></body></html>"; chapters[0].Content != want {
+		t.Errorf("Expected first part %q, got %q", want, chapters[0].Content)
+	}
+	if want := "<html><body><section><h1>Two</h1><p>B</p></section></body></html>"; chapters[1].Content != want {
+		t.Errorf("Expected second part %q, got %q", want, chapters[1].Content)
+	}
+
+	// Documents without the heading are not split
+	chapters, err = epub.GetChapters(WithSplitByHeading("h3"))
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if len(chapters) != 2 {
+		t.Errorf("Expected 2 chapters, got %d", len(chapters))
+	}
+}
+
+func TestEpub_SpineItems(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `<itemref idref="c1"/>`, `<itemref idref="c1" linear="no"/><itemref idref="missing"/>`, 1)
+	epub := newTestEpub(t, files)
+
+	items := epub.SpineItems()
+	if len(items) != 2 || items[0].ID != "c1" || items[1].ID != "c2" {
+		t.Fatalf("Expected spine items [c1 c2], got %+v", items)
+	}
+	if items[0].Href != "text/one.xhtml" {
+		t.Errorf("Expected href text/one.xhtml, got %q", items[0].Href)
+	}
+
+	items, errs := epub.SpineItemsErr()
+	if len(items) != 2 {
+		t.Errorf("Expected 2 spine items, got %d", len(items))
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "missing") {
+		t.Errorf("Expected an error for the unknown idref, got %v", errs)
+	}
+}
+
+func TestEpub_GetItemReader(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	reader, err := epub.GetItemReader(epub.Spine[1].IDRef)
+	if err != nil {
+		t.Fatalf("Failed to get item reader: %v", err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read item: %v", err)
+	}
+
+	expected, err := epub.GetChapterContent(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+
+	if string(content) != expected {
+		t.Error("Expected item content to match chapter content")
+	}
+
+	if _, err := epub.GetItemReader("no-such-id"); err == nil {
+		t.Error("Expected error for unknown item ID, got nil")
+	}
+}
+
+func TestEpub_RootFileDir(t *testing.T) {
+	tests := []struct {
+		rootFile string
+		want     string
+	}{
+		{"OEBPS/content.opf", "OEBPS"},
+		{"content.opf", "."},
+		{"OPS/book/package.opf", "OPS/book"},
+	}
+	for _, tt := range tests {
+		epub := &Epub{RootFile: tt.rootFile}
+		if got := epub.RootFileDir(); got != tt.want {
+			t.Errorf("RootFileDir() with %q = %q, want %q", tt.rootFile, got, tt.want)
+		}
+	}
+}
+
+func TestEpub_ResolveHref(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `href="text/two.xhtml"`, `href="../Shared/./two.xhtml"`, 1)
+	delete(files, "OEBPS/text/two.xhtml")
+	files["Shared/two.xhtml"] = `<html><body><p>Shared chapter</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"text/one.xhtml", "OEBPS/text/one.xhtml"},
+		{"./text/one.xhtml", "OEBPS/text/one.xhtml"},
+		{"../Shared/two.xhtml", "Shared/two.xhtml"},
+		{"../../outside.xhtml", "outside.xhtml"},
+	}
+	for _, tt := range tests {
+		if got := epub.resolveHref(tt.href); got != tt.want {
+			t.Errorf("resolveHref(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+
+	content, err := epub.GetChapterContent(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter with ../ href: %v", err)
+	}
+	if !strings.Contains(content, "Shared chapter") {
+		t.Errorf("Unexpected chapter content: %q", content)
+	}
+}
+
+func TestEpub_PercentEncodedHrefs(t *testing.T) {
+	files := testEPUB3Files()
+	opf := strings.Replace(testNavOPF, `href="text/one.xhtml"`, `href="text/chapter%201.xhtml"`, 1)
+	opf = strings.Replace(opf, `href="text/two.xhtml"`, `href="text/chapter%202.xhtml"`, 1)
+	files["OEBPS/content.opf"] = opf
+	delete(files, "OEBPS/nav.xhtml")
+	delete(files, "OEBPS/text/one.xhtml")
+	delete(files, "OEBPS/text/two.xhtml")
+	files["OEBPS/toc.ncx"] = strings.Replace(testNCX, `src="text/one.xhtml"`, `src="text/chapter%201.xhtml"`, 1)
+	// The first chapter is stored decoded, the second with its encoded name
+	files["OEBPS/text/chapter 1.xhtml"] = `<html><body><p>Decoded</p></body></html>`
+	files["OEBPS/text/chapter%202.xhtml"] = `<html><body><p>Encoded</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if len(chapters) != 2 {
+		t.Fatalf("Expected 2 chapters, got %d", len(chapters))
+	}
+
+	if !strings.Contains(chapters[0].Content, "Decoded") {
+		t.Errorf("Expected decoded entry to be found, got %q", chapters[0].Content)
+	}
+	if !strings.Contains(chapters[1].Content, "Encoded") {
+		t.Errorf("Expected encoded entry to be found, got %q", chapters[1].Content)
+	}
+
+	if chapters[0].Title != "NCX One" {
+		t.Errorf("Expected first chapter title from the NCX, got %q", chapters[0].Title)
+	}
+}
+
+func TestOpen_MissingContainer(t *testing.T) {
+	build := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, content := range files {
+			fw, err := w.Create(name)
+			if err != nil {
+				t.Fatalf("Failed to create zip entry %s: %v", name, err)
+			}
+			io.WriteString(fw, content)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Failed to close zip writer: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	files := testEPUB3Files()
+	epub, err := OpenBytes(build(files))
+	if err != nil {
+		t.Fatalf("Expected single package document to be used, got %v", err)
+	}
+	if epub.RootFile != "OEBPS/content.opf" || epub.GetTitle() != "Nav Test" {
+		t.Errorf("Unexpected rootfile %q with title %q", epub.RootFile, epub.GetTitle())
+	}
+
+	files["other.opf"] = testNavOPF
+	if _, err := OpenBytes(build(files)); !errors.Is(err, ErrInvalidEPUB) {
+		t.Errorf("Expected ErrInvalidEPUB for multiple package documents, got %v", err)
+	}
+
+	if _, err := OpenBytes(build(map[string]string{"mimetype": "application/epub+zip"})); !errors.Is(err, ErrInvalidEPUB) {
+		t.Errorf("Expected ErrInvalidEPUB without package document, got %v", err)
+	}
+}
+
+func TestEpub_SelectRendition(t *testing.T) {
+	files := testEPUB3Files()
+	files["META-INF/container.xml"] = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container" xmlns:rendition="http://www.idpf.org/2013/rendition">
+	<rootfiles>
+		<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml" rendition:layout="reflowable"/>
+		<rootfile full-path="FIXED/content.opf" media-type="application/oebps-package+xml" rendition:layout="pre-paginated" rendition:label="Fixed"/>
+		<rootfile full-path="missing.opf" media-type="application/oebps-package+xml"/>
+	</rootfiles>
+</container>`
+	files["FIXED/content.opf"] = strings.Replace(testNavOPF, "<dc:title>Nav Test</dc:title>", "<dc:title>Fixed Layout</dc:title>", 1)
+	files["FIXED/nav.xhtml"] = `<html><body><nav epub:type="toc"><ol><li><a href="text/one.xhtml">Fixed One</a></li></ol></nav></body></html>`
+	files["FIXED/text/one.xhtml"] = testChapterXHTML
+	epub := newTestEpub(t, files)
+
+	renditions := epub.GetRenditions()
+	if len(renditions) != 3 || renditions[1].Layout != "pre-paginated" || renditions[1].Label != "Fixed" {
+		t.Fatalf("Unexpected renditions: %+v", renditions)
+	}
+	if epub.GetTitle() != "Nav Test" {
+		t.Errorf("Expected first rendition by default, got %q", epub.GetTitle())
+	}
+
+	if err := epub.SelectRendition(1); err != nil {
+		t.Fatalf("Failed to select rendition: %v", err)
+	}
+	if epub.RootFile != "FIXED/content.opf" || epub.GetTitle() != "Fixed Layout" {
+		t.Errorf("Unexpected rendition %q with title %q", epub.RootFile, epub.GetTitle())
+	}
+	if len(epub.TOC.NavMap) != 1 || epub.TOC.NavMap[0].Label() != "Fixed One" {
+		t.Errorf("Expected TOC of the selected rendition, got %+v", epub.TOC.NavMap)
+	}
+
+	if err := epub.SelectRendition(2); err == nil {
+		t.Error("Expected error for rendition with missing package document, got nil")
+	}
+	if epub.RootFile != "FIXED/content.opf" {
+		t.Errorf("Expected failed selection to leave the Epub unchanged, got %q", epub.RootFile)
+	}
+
+	if err := epub.SelectRendition(3); err == nil {
+		t.Error("Expected error for invalid rendition index, got nil")
+	}
+}
+
+func TestEpub_RawPackageAndNCX(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	opf, err := epub.RawPackage()
+	if err != nil || string(opf) != testNavOPF {
+		t.Errorf("Expected raw package document, got %q (%v)", opf, err)
+	}
+
+	ncx, err := epub.RawNCX()
+	if err != nil || string(ncx) != testNCX {
+		t.Errorf("Expected raw NCX, got %q (%v)", ncx, err)
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>`, "", 1)
+	if _, err := newTestEpub(t, files).RawNCX(); !errors.Is(err, ErrNoTOC) {
+		t.Errorf("Expected ErrNoTOC without NCX, got %v", err)
+	}
+}
+
+func TestEpub_GetNavHTML(t *testing.T) {
+	nav, err := newTestEpub(t, testEPUB3Files()).GetNavHTML()
+	if err != nil || nav != testNavXHTML {
+		t.Errorf("Expected raw navigation document, got %q (%v)", nav, err)
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, ` properties="nav"`, "", 1)
+	if _, err := newTestEpub(t, files).GetNavHTML(); !errors.Is(err, ErrNoTOC) {
+		t.Errorf("Expected ErrNoTOC without navigation document, got %v", err)
+	}
+}
+
+func TestNavPoint_Src(t *testing.T) {
+	data := `<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/">
+	<navMap>
+		<navPoint id="n1" playOrder="1">
+			<navLabel><text>One</text></navLabel>
+			<content src="text/one.xhtml#top"/>
+			<navPoint id="n2" playOrder="2"><navLabel><text>Two</text></navLabel><content src="text/two.xhtml"/></navPoint>
+		</navPoint>
+	</navMap>
+</ncx>`
+
+	var ncx NCX
+	if err := xml.Unmarshal([]byte(data), &ncx); err != nil {
+		t.Fatalf("Failed to parse NCX: %v", err)
+	}
+
+	if len(ncx.NavMap) != 1 {
+		t.Fatalf("Expected 1 navPoint, got %d", len(ncx.NavMap))
+	}
+	point := ncx.NavMap[0]
+	if point.Src != "text/one.xhtml#top" || point.Label() != "One" || point.ID != "n1" {
+		t.Errorf("Unexpected navPoint: %+v", point)
+	}
+	if len(point.NavPoints) != 1 || point.NavPoints[0].Src != "text/two.xhtml" {
+		t.Errorf("Expected nested navPoint with src text/two.xhtml, got %+v", point.NavPoints)
+	}
+}
+
+func TestNavPoint_Labels(t *testing.T) {
+	data := `<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" xml:lang="en">
+	<navMap>
+		<navPoint id="n1" playOrder="1">
+			<navLabel><text>Chapter One</text></navLabel>
+			<navLabel xml:lang="zh"><text>第一章</text></navLabel>
+			<navLabel><text xml:lang="fr">Chapitre un</text></navLabel>
+			<content src="text/one.xhtml"/>
+		</navPoint>
+		<navPoint id="n2" playOrder="2"><content src="text/two.xhtml"/></navPoint>
+	</navMap>
+</ncx>`
+
+	var ncx NCX
+	if err := xml.Unmarshal([]byte(data), &ncx); err != nil {
+		t.Fatalf("Failed to parse NCX: %v", err)
+	}
+
+	want := []LocalizedText{
+		{Text: "Chapter One"},
+		{Lang: "zh", Text: "第一章"},
+		{Lang: "fr", Text: "Chapitre un"},
+	}
+	if !reflect.DeepEqual(ncx.NavMap[0].Labels, want) {
+		t.Errorf("Expected labels %+v, got %+v", want, ncx.NavMap[0].Labels)
+	}
+	if label := ncx.NavMap[0].Label(); label != "Chapter One" {
+		t.Errorf("Expected default label Chapter One, got %q", label)
+	}
+	if label := ncx.NavMap[1].Label(); label != "" {
+		t.Errorf("Expected no label, got %q", label)
+	}
+}
@@ -0,0 +1,53 @@
+package epub
+
+import (
+	"errors"
+)
+
+// Sentinel errors returned by this package
+//
+// Errors are usually wrapped with additional context, such as the path of
+// the missing file or the offending chapter index, so they should be tested
+// with errors.Is rather than compared directly.
+var (
+	// ErrInvalidEPUB is returned when the archive is not a readable EPUB,
+	// for example because it is not a zip file or its container or package
+	// document is missing or malformed
+	ErrInvalidEPUB = errors.New("invalid epub")
+
+	// ErrFileNotFound is returned when a file does not exist in the archive
+	ErrFileNotFound = errors.New("file not found")
+
+	// ErrFileTooLarge is returned when a file exceeds the size limit that
+	// applies when reading it
+	ErrFileTooLarge = errors.New("file too large")
+
+	// ErrChapterOutOfRange is returned when a chapter index does not refer
+	// to an item of the spine
+	ErrChapterOutOfRange = errors.New("chapter index out of range")
+
+	// ErrNotHTML is returned when a spine item that is expected to be a
+	// chapter is not an HTML document
+	ErrNotHTML = errors.New("not an HTML document")
+
+	// ErrNoTOC is returned when the EPUB has no table of contents
+	ErrNoTOC = errors.New("no table of contents found")
+
+	// ErrNoCover is returned when the EPUB does not contain a cover image
+	ErrNoCover = errors.New("no cover image found")
+
+	// ErrDRMProtected is returned when the EPUB's content is encrypted with
+	// DRM
+	//
+	// The constructors return it together with the parsed Epub, so that the
+	// metadata and other unencrypted parts of the book remain accessible.
+	ErrDRMProtected = errors.New("epub is DRM protected")
+
+	// ErrWrongPassword is returned by OpenEncryptedZip when the password
+	// does not decrypt the archive
+	ErrWrongPassword = errors.New("wrong password")
+
+	// ErrNotEncrypted is returned by OpenEncryptedZip when the archive has
+	// no AES encrypted entries
+	ErrNotEncrypted = errors.New("zip archive is not encrypted")
+)
@@ -0,0 +1,51 @@
+package epub
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	files := testEPUB3Files()
+	epub := newTestEpub(t, files)
+
+	if _, err := epub.GetChapterContent(10); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+
+	if _, err := epub.GetFileReader("OEBPS/missing.xhtml"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+
+	delete(files, "OEBPS/text/two.xhtml")
+	if _, err := newTestEpub(t, files).GetChapterContent(1); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound for missing chapter, got %v", err)
+	}
+
+	files = testEPUB3Files()
+	files["OEBPS/content.opf"] = `<package xmlns="http://www.idpf.org/2007/opf" version="2.0">
+	<manifest>
+		<item id="css" href="style.css" media-type="text/css"/>
+	</manifest>
+	<spine><itemref idref="css"/></spine>
+</package>`
+	epub = newTestEpub(t, files)
+
+	if _, err := epub.GetChapterContent(0); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("Expected ErrNotHTML, got %v", err)
+	}
+
+	if _, err := epub.GetTOC(); !errors.Is(err, ErrNoTOC) {
+		t.Errorf("Expected ErrNoTOC, got %v", err)
+	}
+
+	if _, err := OpenBytes([]byte("not a zip archive")); !errors.Is(err, ErrInvalidEPUB) {
+		t.Errorf("Expected ErrInvalidEPUB for non-zip data, got %v", err)
+	}
+
+	files = testEPUB3Files()
+	files["OEBPS/content.opf"] = "<package><metadata>"
+	if _, err := OpenBytes(buildTestZip(t, files)); !errors.Is(err, ErrInvalidEPUB) {
+		t.Errorf("Expected ErrInvalidEPUB for malformed package document, got %v", err)
+	}
+}
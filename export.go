@@ -0,0 +1,325 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// idAttrPattern matches the id attribute of a start tag
+var idAttrPattern = regexp.MustCompile(`(?i)(\sid\s*=\s*)("[^"]*"|'[^']*')`)
+
+// hrefAttrPattern matches the href attribute of a start tag
+var hrefAttrPattern = regexp.MustCompile(`(?i)(\s(?:xlink:)?href\s*=\s*)("[^"]*"|'[^']*')`)
+
+// ToSingleHTML returns the whole book as a single HTML document
+//
+// The body content of each chapter returned by GetChapters is wrapped in a
+// <section> element with the ID "chapter-N", where N is the chapter's Order,
+// and the sections are joined in reading order under a synthesized <head>
+// holding the book title. Element IDs are prefixed with the section ID so
+// that they remain unique, and links between chapters, including links to
+// fragments, are rewritten to point at the corresponding anchors in the
+// combined document. Other links are left unchanged. The options are
+// applied as for GetChapters, so chapters exceeding WithMaxContentLength are
+// omitted and cancelling the context given with WithContext aborts the
+// conversion.
+//
+// Example:
+//
+//	doc, err := e.ToSingleHTML()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("preview.html", []byte(doc), 0o644)
+func (e *Epub) ToSingleHTML(opts ...Option) (string, error) {
+	type section struct {
+		id      string
+		path    string
+		content []byte
+	}
+
+	var sections []section
+	anchors := make(map[string]string)
+	// parts counts the sections of each document, which is split into
+	// several with WithSplitByHeading
+	parts := make(map[string]int)
+
+	for chapter, err := range e.ChaptersIter(opts...) {
+		if err != nil {
+			return "", err
+		}
+
+		s := section{
+			id:      fmt.Sprintf("chapter-%d", chapter.Order),
+			path:    e.resolveHref(chapter.Href),
+			content: []byte(chapter.Content),
+		}
+		sections = append(sections, s)
+		if parts[s.path] == 0 {
+			anchors[s.path] = s.id
+		}
+		parts[s.path]++
+	}
+
+	// Fragments of split documents are found in the section holding them
+	for _, s := range sections {
+		if parts[s.path] < 2 {
+			continue
+		}
+		_, ids, err := scanLinks(s.content)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert chapter %s: %w", s.path, err)
+		}
+		for id := range ids {
+			anchors[s.path+"#"+id] = s.id
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\"/>\n")
+	sb.WriteString("<title>" + html.EscapeString(e.GetTitle()) + "</title>\n")
+	sb.WriteString("</head>\n<body>\n")
+
+	for _, s := range sections {
+		body, err := sectionBody(s.content, s.path, s.id, anchors)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert chapter %s: %w", s.path, err)
+		}
+
+		sb.WriteString("<section id=\"" + s.id + "\">\n")
+		sb.WriteString(strings.TrimSpace(body))
+		sb.WriteString("\n</section>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String(), nil
+}
+
+// sectionBody returns the content of the <body> element of the chapter at
+// docPath, rewriting its IDs and links for inclusion in a single document
+//
+// IDs are prefixed with prefix. Links to fragments of the chapter itself or
+// to other chapters listed in anchors, keyed by zip path, are rewritten to
+// the corresponding in-document anchors. If the chapter has no <body>
+// element, its whole content is used.
+func sectionBody(content []byte, docPath, prefix string, anchors map[string]string) (string, error) {
+	var sb strings.Builder
+	start, end := int64(0), int64(len(content))
+	inBody := false
+	last := int64(-1)
+
+	d := newHTMLDecoder(content)
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if !inBody {
+				if strings.EqualFold(t.Name.Local, "body") {
+					inBody = true
+					start = d.InputOffset()
+					last = start
+				}
+				continue
+			}
+
+			tagEnd := d.InputOffset()
+			tag := string(content[offset:tagEnd])
+			tag = idAttrPattern.ReplaceAllStringFunc(tag, func(attr string) string {
+				m := idAttrPattern.FindStringSubmatch(attr)
+				quote := m[2][:1]
+				return m[1] + quote + prefix + "-" + m[2][1:len(m[2])-1] + quote
+			})
+			tag = hrefAttrPattern.ReplaceAllStringFunc(tag, func(attr string) string {
+				m := hrefAttrPattern.FindStringSubmatch(attr)
+				quote := m[2][:1]
+				ref := html.UnescapeString(m[2][1 : len(m[2])-1])
+
+				anchor, ok := sectionAnchor(docPath, ref, prefix, anchors)
+				if !ok {
+					return attr
+				}
+				return m[1] + quote + html.EscapeString(anchor) + quote
+			})
+
+			sb.Write(content[last:offset])
+			sb.WriteString(tag)
+			last = tagEnd
+		case xml.EndElement:
+			if inBody && strings.EqualFold(t.Name.Local, "body") {
+				end = offset
+				sb.Write(content[last:end])
+				return sb.String(), nil
+			}
+		}
+	}
+
+	if !inBody {
+		return string(content[start:end]), nil
+	}
+	sb.Write(content[last:end])
+	return sb.String(), nil
+}
+
+// sectionAnchor returns the in-document anchor a link found in the chapter
+// at docPath points to, if it refers to the chapter itself or to another
+// chapter listed in anchors
+//
+// anchors may also map "path#id" to the section holding the element id of a
+// document split into several sections.
+func sectionAnchor(docPath, ref, prefix string, anchors map[string]string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || isExternalRef(ref) {
+		return "", false
+	}
+
+	var fragment string
+	if i := strings.Index(ref, "#"); i >= 0 {
+		ref, fragment = ref[:i], ref[i+1:]
+	}
+
+	targetPath := docPath
+	if ref != "" {
+		targetPath = resolveRef(docPath, ref)
+	}
+	if target, ok := anchors[targetPath+"#"+fragment]; ok && fragment != "" {
+		return "#" + target + "-" + fragment, true
+	}
+
+	target := prefix
+	if ref != "" {
+		var ok bool
+		if target, ok = anchors[targetPath]; !ok {
+			return "", false
+		}
+	}
+
+	if fragment == "" {
+		return "#" + target, true
+	}
+	return "#" + target + "-" + fragment, true
+}
+
+// ToText writes the plain text of the whole book to w
+//
+// The chapters returned by GetChapters are converted to text as by
+// GetChapterText and written in reading order, each preceded by a line
+// holding its title and a blank line. Chapters are separated by two blank
+// lines. Since chapters are read and written one at a time, memory usage
+// stays flat even for very large books. The options are applied as for
+// GetChapters.
+//
+// Example:
+//
+//	f, err := os.Create("book.txt")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	if err := e.ToText(f); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *Epub) ToText(w io.Writer, opts ...Option) error {
+	first := true
+
+	for chapter, err := range e.ChaptersIter(opts...) {
+		if err != nil {
+			return err
+		}
+
+		text, err := htmlToText([]byte(chapter.Content))
+		if err != nil {
+			return fmt.Errorf("failed to extract chapter text: %w", err)
+		}
+
+		var sb strings.Builder
+		if !first {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(chapter.Title)
+		sb.WriteString("\n\n")
+		if text != "" {
+			sb.WriteString(text)
+			sb.WriteString("\n")
+		}
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+		first = false
+	}
+
+	return nil
+}
+
+// StreamText writes the plain text of the whole book to w, announcing each
+// chapter to onChapter before its text is written
+//
+// The chapters returned by GetChapters are converted to text as by
+// GetChapterText and written in reading order, separated by a blank line.
+// Unlike ToText, chapter titles are not written to w but passed to
+// onChapter together with the zero-based spine index of the chapter's
+// document, as used by GetChapterContent; the parts of a document split
+// with WithSplitByHeading share its index. This suits pipelines such as
+// text-to-speech that announce chapter boundaries out of band. Each chapter
+// is written with a single call to w, after onChapter returns. onChapter may
+// be nil. The options are applied as for GetChapters, so the context given
+// with WithContext is checked before every chapter.
+//
+// Example:
+//
+//	err := e.StreamText(w, func(index int, title string) {
+//		fmt.Printf("Now reading chapter %d: %s\n", index+1, title)
+//	}, epub.WithContext(ctx))
+func (e *Epub) StreamText(w io.Writer, onChapter func(index int, title string), opts ...Option) error {
+	first := true
+
+	for chapter, err := range e.ChaptersIter(opts...) {
+		if err != nil {
+			return err
+		}
+
+		text, err := htmlToText([]byte(chapter.Content))
+		if err != nil {
+			return fmt.Errorf("failed to extract chapter text: %w", err)
+		}
+
+		if onChapter != nil {
+			// Order is no spine position once documents are split or the
+			// cover page is skipped
+			index, err := e.SpineIndexForHref(chapter.Href)
+			if err != nil {
+				return err
+			}
+			onChapter(index, chapter.Title)
+		}
+
+		var sb strings.Builder
+		if !first {
+			sb.WriteString("\n")
+		}
+		if text != "" {
+			sb.WriteString(text)
+			sb.WriteString("\n")
+		}
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+		first = false
+	}
+
+	return nil
+}
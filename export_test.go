@@ -0,0 +1,190 @@
+package epub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEpub_ToSingleHTML(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>One</title><style>p { color: red; }</style></head>
+<body>
+	<h1 id="top">One</h1>
+	<p>See <a href="two.xhtml#s1">section 1</a>, <a href="two.xhtml">chapter two</a> and <a href="#top">the top</a>.</p>
+	<p><a href="https://example.com/">Web</a></p>
+</body>
+</html>`
+	files["OEBPS/text/two.xhtml"] = `<html><body><h2 id='s1'>Section 1</h2></body></html>`
+	epub := newTestEpub(t, files)
+
+	doc, err := epub.ToSingleHTML()
+	if err != nil {
+		t.Fatalf("Failed to convert to single HTML: %v", err)
+	}
+
+	for _, want := range []string{
+		"<title>Nav Test</title>",
+		`<section id="chapter-1">`,
+		`<h1 id="chapter-1-top">One</h1>`,
+		`<a href="#chapter-2-s1">section 1</a>`,
+		`<a href="#chapter-2">chapter two</a>`,
+		`<a href="#chapter-1-top">the top</a>`,
+		`<a href="https://example.com/">Web</a>`,
+		`<section id="chapter-2">`,
+		`<h2 id='chapter-2-s1'>Section 1</h2>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected document to contain %s\n%s", want, doc)
+		}
+	}
+
+	if strings.Contains(doc, "color: red") || strings.Count(doc, "<body>") != 1 {
+		t.Errorf("Expected only the body content of each chapter\n%s", doc)
+	}
+
+	if strings.Index(doc, `id="chapter-1"`) > strings.Index(doc, `id="chapter-2"`) {
+		t.Error("Expected chapters in reading order")
+	}
+
+	doc, err = epub.ToSingleHTML(WithMaxContentLength(100))
+	if err != nil {
+		t.Fatalf("Failed to convert to single HTML: %v", err)
+	}
+	if strings.Contains(doc, `id="chapter-1"`) || !strings.Contains(doc, `id="chapter-2"`) {
+		t.Errorf("Expected chapters exceeding the maximum length to be omitted\n%s", doc)
+	}
+}
+
+func TestEpub_ToSingleHTML_SplitByHeading(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html><body>
+<h1>Alpha</h1><p>See <a href="#b2">the second part</a>.</p>
+<h1 id="beta">Beta</h1><p id="b2">Second.</p>
+</body></html>`
+	files["OEBPS/text/two.xhtml"] = `<html><body><p><a href="one.xhtml#b2">Back</a> to <a href="one.xhtml">the start</a>.</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	doc, err := epub.ToSingleHTML(WithSplitByHeading("h1"))
+	if err != nil {
+		t.Fatalf("Failed to convert split chapters to single HTML: %v", err)
+	}
+
+	for _, want := range []string{
+		`<section id="chapter-1">`,
+		`<section id="chapter-2">`,
+		`<section id="chapter-3">`,
+		`<a href="#chapter-2-b2">the second part</a>`,
+		`<p id="chapter-2-b2">Second.</p>`,
+		`<a href="#chapter-2-b2">Back</a>`,
+		`<a href="#chapter-1">the start</a>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected document to contain %s\n%s", want, doc)
+		}
+	}
+}
+
+func TestEpub_ToText(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html><head><title>Ignored</title></head><body><p>First &amp; foremost.</p><p>Second paragraph.</p></body></html>`
+	files["OEBPS/text/two.xhtml"] = `<html><body><p>The end.</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	var sb strings.Builder
+	if err := epub.ToText(&sb); err != nil {
+		t.Fatalf("Failed to export text: %v", err)
+	}
+
+	want := "Part One\n\nFirst & foremost.\n\nSecond paragraph.\n\n\nPart Two\n\nThe end.\n"
+	if sb.String() != want {
+		t.Errorf("Unexpected text:\ngot  %q\nwant %q", sb.String(), want)
+	}
+}
+
+func TestEpub_ToText_SplitByHeading(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html><body><section><h1>Alpha</h1><p>First.</p><h1>Beta</h1><p>Second.</p></section></body></html>`
+	files["OEBPS/text/two.xhtml"] = `<html><body><p>The end.</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	var sb strings.Builder
+	if err := epub.ToText(&sb, WithSplitByHeading("h1")); err != nil {
+		t.Fatalf("Failed to export split text: %v", err)
+	}
+
+	want := "Alpha\n\nAlpha\n\nFirst.\n\n\nBeta\n\nBeta\n\nSecond.\n\n\nPart Two\n\nThe end.\n"
+	if sb.String() != want {
+		t.Errorf("Unexpected text:\ngot  %q\nwant %q", sb.String(), want)
+	}
+
+	sb.Reset()
+	var indexes []int
+	err := epub.StreamText(&sb, func(index int, title string) {
+		indexes = append(indexes, index)
+	}, WithSplitByHeading("h1"))
+	if err != nil {
+		t.Fatalf("Failed to stream split text: %v", err)
+	}
+	if want := []int{0, 0, 1}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("Unexpected spine indexes: got %v, want %v", indexes, want)
+	}
+	if want := "Alpha\n\nFirst.\n\nBeta\n\nSecond.\n\nThe end.\n"; sb.String() != want {
+		t.Errorf("Unexpected streamed text:\ngot  %q\nwant %q", sb.String(), want)
+	}
+}
+
+// eventWriter records writes as events, to check their order relative to
+// other callbacks
+type eventWriter struct {
+	events *[]string
+}
+
+func (w eventWriter) Write(p []byte) (int, error) {
+	*w.events = append(*w.events, "text: "+string(p))
+	return len(p), nil
+}
+
+func TestEpub_StreamText(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html><body><p>First &amp; foremost.</p><p>Second paragraph.</p></body></html>`
+	files["OEBPS/text/two.xhtml"] = `<html><body><p>The end.</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	var events []string
+	err := epub.StreamText(eventWriter{&events}, func(index int, title string) {
+		events = append(events, fmt.Sprintf("chapter %d: %s", index, title))
+	})
+	if err != nil {
+		t.Fatalf("Failed to stream text: %v", err)
+	}
+
+	want := []string{
+		"chapter 0: Part One",
+		"text: First & foremost.\n\nSecond paragraph.\n",
+		"chapter 1: Part Two",
+		"text: \nThe end.\n",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Unexpected events:\ngot  %q\nwant %q", events, want)
+	}
+
+	var sb strings.Builder
+	if err := epub.StreamText(&sb, nil); err != nil {
+		t.Fatalf("Failed to stream text without a callback: %v", err)
+	}
+	if sb.String() != "First & foremost.\n\nSecond paragraph.\n\nThe end.\n" {
+		t.Errorf("Unexpected text: %q", sb.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := epub.StreamText(&sb, nil, WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
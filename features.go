@@ -0,0 +1,158 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ChapterFeatures describes the potentially unsafe or demanding features a
+// chapter uses
+//
+// Each flag is set if the chapter's manifest item declares the matching EPUB 3
+// property ("scripted", "mathml", "svg" or "remote-resources") or if a scan of
+// its content finds the feature, so books that omit the properties are still
+// detected.
+type ChapterFeatures struct {
+	// HasScript is set for <script> elements, on* event handler attributes
+	// and javascript: URLs
+	HasScript bool
+
+	// HasMathML is set for MathML <math> elements
+	HasMathML bool
+
+	// HasSVG is set for inline <svg> elements
+	HasSVG bool
+
+	// HasRemoteResources is set for resources such as images, stylesheets,
+	// scripts and media that are loaded from outside the EPUB; plain links
+	// to external sites do not count
+	HasRemoteResources bool
+}
+
+// resourceAttrs lists the attributes that load a resource when rendering an
+// element; href only does so for the elements in resourceHrefElements
+var resourceAttrs = map[string]bool{
+	"src":    true,
+	"href":   true,
+	"data":   true,
+	"poster": true,
+	"srcset": true,
+}
+
+// resourceHrefElements lists the elements whose href attribute loads a
+// resource
+var resourceHrefElements = map[string]bool{
+	"image": true,
+	"link":  true,
+	"use":   true,
+}
+
+// ChapterFeatures reports which scripting, MathML, SVG and remote resource
+// features a chapter uses
+//
+// The index is zero-based, as for GetChapterContent. This lets a reading
+// system decide how to sandbox a chapter, or whether to render it at all,
+// before passing it to a browser engine.
+//
+// Example:
+//
+//	features, err := e.ChapterFeatures(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if features.HasScript || features.HasRemoteResources {
+//		fmt.Println("chapter needs a sandbox")
+//	}
+func (e *Epub) ChapterFeatures(chapterIndex int) (ChapterFeatures, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return ChapterFeatures{}, err
+	}
+
+	content, err := e.getFile(e.resolveHref(item.Href))
+	if err != nil {
+		return ChapterFeatures{}, fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	features, err := scanFeatures(content)
+	if err != nil {
+		return ChapterFeatures{}, fmt.Errorf("failed to scan chapter: %w", err)
+	}
+
+	features.HasScript = features.HasScript || item.HasProperty("scripted")
+	features.HasMathML = features.HasMathML || item.HasProperty("mathml")
+	features.HasSVG = features.HasSVG || item.HasProperty("svg")
+	features.HasRemoteResources = features.HasRemoteResources || item.HasProperty("remote-resources")
+	return features, nil
+}
+
+// scanFeatures scans the elements and attributes of an (X)HTML document for
+// the features reported by ChapterFeatures
+func scanFeatures(content []byte) (ChapterFeatures, error) {
+	var features ChapterFeatures
+
+	d := newHTMLDecoder(content)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return features, nil
+		}
+		if err != nil {
+			return features, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		name := strings.ToLower(start.Name.Local)
+		switch name {
+		case "script":
+			features.HasScript = true
+		case "math":
+			features.HasMathML = true
+		case "svg":
+			features.HasSVG = true
+		}
+
+		for _, attr := range start.Attr {
+			attrName := strings.ToLower(attr.Name.Local)
+			value := strings.TrimSpace(attr.Value)
+
+			if strings.HasPrefix(attrName, "on") {
+				features.HasScript = true
+			}
+			if strings.HasPrefix(strings.ToLower(value), "javascript:") {
+				features.HasScript = true
+			}
+			if resourceAttrs[attrName] && (attrName != "href" || resourceHrefElements[name]) && isRemoteRef(value) {
+				features.HasRemoteResources = true
+			}
+		}
+	}
+}
+
+// isRemoteRef reports whether a reference, or any candidate of a srcset
+// list, points at a network location
+func isRemoteRef(ref string) bool {
+	for _, candidate := range strings.Split(ref, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "//") {
+			return true
+		}
+		if u, err := url.Parse(fields[0]); err == nil {
+			switch strings.ToLower(u.Scheme) {
+			case "http", "https", "ftp":
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package epub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEpub_ChapterFeatures(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `href="text/two.xhtml" media-type="application/xhtml+xml"`,
+		`href="text/two.xhtml" media-type="application/xhtml+xml" properties="scripted remote-resources"`, 1)
+	files["OEBPS/text/one.xhtml"] = `<html xmlns="http://www.w3.org/1999/xhtml">
+<head><link rel="stylesheet" href="https://example.com/style.css"/></head>
+<body>
+	<p><a href="https://example.com/">A plain link</a></p>
+	<m:math xmlns:m="http://www.w3.org/1998/Math/MathML"><m:mi>x</m:mi></m:math>
+	<svg xmlns="http://www.w3.org/2000/svg"><image href="../images/local.png"/></svg>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	features, err := epub.ChapterFeatures(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter features: %v", err)
+	}
+	want := ChapterFeatures{HasMathML: true, HasSVG: true, HasRemoteResources: true}
+	if features != want {
+		t.Errorf("Expected features %+v, got %+v", want, features)
+	}
+
+	// Declared properties are honored even if the content does not show them
+	features, err = epub.ChapterFeatures(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter features: %v", err)
+	}
+	if want := (ChapterFeatures{HasScript: true, HasRemoteResources: true}); features != want {
+		t.Errorf("Expected features %+v, got %+v", want, features)
+	}
+
+	if _, err := epub.ChapterFeatures(99); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
+
+func TestScanFeatures(t *testing.T) {
+	tests := []struct {
+		content string
+		want    ChapterFeatures
+	}{
+		{`<html><body><p>Plain</p><a href="http://example.com/">link</a></body></html>`, ChapterFeatures{}},
+		{`<html><body><script src="app.js"></script></body></html>`, ChapterFeatures{HasScript: true}},
+		{`<html><body><p onclick="go()">Click</p></body></html>`, ChapterFeatures{HasScript: true}},
+		{`<html><body><a href=" JavaScript:go()">Go</a></body></html>`, ChapterFeatures{HasScript: true}},
+		{`<html><body><img src="//cdn.example.com/a.png"/></body></html>`, ChapterFeatures{HasRemoteResources: true}},
+		{`<html><body><img srcset="a.png 1x, https://example.com/b.png 2x"/></body></html>`, ChapterFeatures{HasRemoteResources: true}},
+		{`<html><body><img src="data:image/png;base64,iVBORw0KGgo="/></body></html>`, ChapterFeatures{}},
+	}
+	for _, tt := range tests {
+		got, err := scanFeatures([]byte(tt.content))
+		if err != nil {
+			t.Errorf("Failed to scan %q: %v", tt.content, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("scanFeatures(%q) = %+v, want %+v", tt.content, got, tt.want)
+		}
+	}
+}
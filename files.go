@@ -0,0 +1,123 @@
+package epub
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WalkFiles calls fn for every file of the EPUB
+//
+// Files are visited in archive order, or in lexical order for books opened
+// with OpenFS; directory entries are skipped. size is the uncompressed size
+// of the file, or -1 if it is not known. open opens the file for reading and
+// may be called any number of times, or not at all, so that only the files
+// the caller needs are decompressed; the caller must close the returned
+// reader. Unlike GetFileReader, open returns the stored bytes, without
+// de-obfuscating fonts, which is what repackaging the book requires. If fn
+// returns an error, the walk stops and the error is returned.
+//
+// Example:
+//
+//	err := e.WalkFiles(func(name string, size int64, open func() (io.ReadCloser, error)) error {
+//		fmt.Printf("%8d %s\n", size, name)
+//		return nil
+//	})
+func (e *Epub) WalkFiles(fn func(name string, size int64, open func() (io.ReadCloser, error)) error) error {
+	s, hasSize := e.src.(sizer)
+
+	for _, name := range e.src.list() {
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+
+		size := int64(-1)
+		if hasSize {
+			if n, ok := s.size(name); ok {
+				size = n
+			}
+		}
+
+		open := func() (io.ReadCloser, error) {
+			return e.src.open(name)
+		}
+		if err := fn(name, size, open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Extract writes every file of the EPUB to destDir
+//
+// The directory structure of the archive is preserved below destDir, and
+// missing directories, including destDir itself, are created. Fonts
+// obfuscated as declared in META-INF/encryption.xml are de-obfuscated, as by
+// GetFileReader, so the extracted tree can be used directly or opened again
+// with OpenFS. Files whose names would place them outside destDir are
+// rejected. Existing files are overwritten. Extract stops at the first error;
+// ExtractBestEffort extracts as much as possible instead.
+//
+// Example:
+//
+//	if err := e.Extract("book"); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *Epub) Extract(destDir string) error {
+	return e.WalkFiles(func(name string, _ int64, _ func() (io.ReadCloser, error)) error {
+		return e.extractFile(destDir, name)
+	})
+}
+
+// ExtractBestEffort writes every file of the EPUB to destDir like Extract,
+// but continues past files that fail to extract
+//
+// The returned error joins the errors of all failed files, or is nil if every
+// file was extracted.
+func (e *Epub) ExtractBestEffort(destDir string) error {
+	var errs []error
+	e.WalkFiles(func(name string, _ int64, _ func() (io.ReadCloser, error)) error {
+		if err := e.extractFile(destDir, name); err != nil {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
+// extractFile writes a single file of the EPUB below destDir
+func (e *Epub) extractFile(destDir, name string) error {
+	rel := filepath.FromSlash(name)
+	if isUnsafePath(name) || !filepath.IsLocal(rel) {
+		return fmt.Errorf("refusing to extract %s: unsafe file name", name)
+	}
+	dest := filepath.Join(destDir, rel)
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	// The name is an entry name, which findFile could take for the
+	// percent-encoded form of another entry
+	rc, err := e.openEntry(name)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package epub
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEpub_WalkFiles(t *testing.T) {
+	files := testEPUB3Files()
+	epub := newTestEpub(t, files)
+
+	visited := make(map[string]bool)
+	err := epub.WalkFiles(func(name string, size int64, open func() (io.ReadCloser, error)) error {
+		visited[name] = true
+		if name != "OEBPS/text/one.xhtml" {
+			return nil
+		}
+
+		if size != int64(len(testChapterXHTML)) {
+			t.Errorf("Expected size %d, got %d", len(testChapterXHTML), size)
+		}
+		rc, err := open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			return err
+		}
+		if string(content) != testChapterXHTML {
+			t.Errorf("Unexpected content: %q", content)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk files: %v", err)
+	}
+
+	for name := range files {
+		if !visited[name] {
+			t.Errorf("Expected %s to be visited", name)
+		}
+	}
+	if !visited["mimetype"] || len(visited) != len(files)+1 {
+		t.Errorf("Expected every entry to be visited once, got %v", visited)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = epub.WalkFiles(func(string, int64, func() (io.ReadCloser, error)) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("Expected the walk to stop at the first error, got %v after %d files", err, count)
+	}
+}
+
+func TestEpub_Extract(t *testing.T) {
+	font := make([]byte, 2000)
+	for i := range font {
+		font[i] = byte(i * 7)
+	}
+	const identifier = "urn:uuid:12340000-0000-0000-0000-000000000000"
+	key := sha1.Sum([]byte(identifier))
+	obfuscated := make([]byte, len(font))
+	copy(obfuscated, font)
+	for i := 0; i < 1040; i++ {
+		obfuscated[i] ^= key[i%len(key)]
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, "urn:uuid:1234", identifier, 1)
+	files["OEBPS/fonts/font.otf"] = string(obfuscated)
+	files["META-INF/encryption.xml"] = testEncryptionXML("OEBPS/fonts/font.otf", algorithmIDPFFont)
+	epub := newTestEpub(t, files)
+
+	dir := filepath.Join(t.TempDir(), "book")
+	if err := epub.Extract(dir); err != nil {
+		t.Fatalf("Failed to extract EPUB: %v", err)
+	}
+
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("Failed to read extracted %s: %v", name, err)
+			continue
+		}
+		want := []byte(content)
+		if name == "OEBPS/fonts/font.otf" {
+			want = font
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Unexpected content of extracted %s", name)
+		}
+	}
+
+	extracted, err := OpenFS(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("Failed to open extracted EPUB: %v", err)
+	}
+	if extracted.GetTitle() != epub.GetTitle() {
+		t.Errorf("Expected extracted EPUB to have title %q, got %q", epub.GetTitle(), extracted.GetTitle())
+	}
+}
+
+func TestEpub_Extract_PercentEncodedNames(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/a%20b.xhtml"] = "encoded"
+	files["OEBPS/a b.xhtml"] = "plain"
+	epub := newTestEpub(t, files)
+
+	dir := t.TempDir()
+	if err := epub.Extract(dir); err != nil {
+		t.Fatalf("Failed to extract EPUB: %v", err)
+	}
+	for _, name := range []string{"OEBPS/a%20b.xhtml", "OEBPS/a b.xhtml"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("Failed to read extracted %s: %v", name, err)
+		} else if string(got) != files[name] {
+			t.Errorf("Expected %s to contain %q, got %q", name, files[name], got)
+		}
+	}
+}
+
+func TestEpub_ExtractBestEffort(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	// A directory in place of a file makes that one file fail to extract
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "OEBPS", "text", "one.xhtml"), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	if err := epub.Extract(dir); err == nil {
+		t.Error("Expected Extract to fail")
+	}
+
+	err := epub.ExtractBestEffort(dir)
+	if err == nil || !strings.Contains(err.Error(), "OEBPS/text/one.xhtml") {
+		t.Errorf("Expected an error for the blocked file, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "OEBPS", "text", "two.xhtml")); err != nil {
+		t.Errorf("Expected the remaining files to be extracted, got %v", err)
+	}
+
+	if err := epub.extractFile(dir, "../evil.xhtml"); err == nil {
+		t.Error("Expected unsafe file names to be rejected")
+	}
+}
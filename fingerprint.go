@@ -0,0 +1,60 @@
+package epub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Fingerprint returns a content-based identifier of the book
+//
+// The fingerprint is the hex-encoded SHA-256 of the package's unique
+// identifier and the sorted zip paths and uncompressed sizes of all manifest
+// items. It does not depend on the file name, the order of the archive
+// entries or their compression, so re-zipping a book yields the same
+// fingerprint as long as its content is preserved. Only the zip central
+// directory is consulted; no file is decompressed. Manifest items whose file
+// is missing are included with a size of -1.
+//
+// Example:
+//
+//	id, err := e.Fingerprint()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if seen[id] {
+//		fmt.Println("duplicate book")
+//	}
+func (e *Epub) Fingerprint() (string, error) {
+	s, ok := e.src.(sizer)
+	if !ok {
+		return "", fmt.Errorf("file sizes are not available")
+	}
+
+	entries := make([]string, 0, len(e.Manifest))
+	for _, item := range e.Manifest {
+		itemPath := e.resolveHref(item.Href)
+
+		size := int64(-1)
+		if name, ok := e.findFile(itemPath); ok {
+			if n, ok := s.size(name); ok {
+				size = n
+			}
+		}
+
+		entries = append(entries, unescapeHref(itemPath)+"\t"+strconv.FormatInt(size, 10))
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	h.Write([]byte(e.Metadata.Identifier))
+	h.Write([]byte{'\n'})
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,42 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEpub_Fingerprint(t *testing.T) {
+	fingerprint := func(files map[string]string) string {
+		t.Helper()
+		id, err := newTestEpub(t, files).Fingerprint()
+		if err != nil {
+			t.Fatalf("Failed to compute fingerprint: %v", err)
+		}
+		return id
+	}
+
+	base := fingerprint(testEPUB3Files())
+	if len(base) != 64 {
+		t.Fatalf("Expected hex SHA-256 fingerprint, got %q", base)
+	}
+
+	// Map iteration makes buildTestZip write the entries in a different
+	// order each time, which must not affect the fingerprint
+	for i := 0; i < 5; i++ {
+		if id := fingerprint(testEPUB3Files()); id != base {
+			t.Fatalf("Expected fingerprint to be stable, got %q and %q", base, id)
+		}
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] += "<!-- more -->"
+	if fingerprint(files) == base {
+		t.Error("Expected fingerprint to change when a file size changes")
+	}
+
+	files = testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, "urn:uuid:1234", "urn:uuid:9999", 1)
+	if fingerprint(files) == base {
+		t.Error("Expected fingerprint to change with the unique identifier")
+	}
+}
@@ -0,0 +1,135 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GetChapterFragment returns the part of a chapter a href's fragment points to
+//
+// The href is interpreted relative to the package document directory, like
+// TOCEntry.Href and GetChapterByHref, and must contain a "#fragment". The
+// returned HTML starts at the element whose id is the fragment. If that
+// element is a heading, or lies inside one, the fragment runs from the
+// heading up to its next sibling heading of the same or a higher rank, or to
+// the end of the heading's parent element, such as <body> or a <section>.
+// Otherwise the fragment is the element itself, including its content. The
+// markup is returned exactly as it appears in the chapter.
+//
+// Example:
+//
+//	for _, entry := range toc {
+//		html, err := e.GetChapterFragment(entry.Href + "#" + entry.Fragment)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Println(html)
+//	}
+func (e *Epub) GetChapterFragment(href string) (string, error) {
+	i := strings.Index(href, "#")
+	if i < 0 || i == len(href)-1 {
+		return "", fmt.Errorf("href has no fragment: %s", href)
+	}
+	id := unescapeHref(href[i+1:])
+
+	index, err := e.SpineIndexForHref(href)
+	if err != nil {
+		return "", err
+	}
+	item, err := e.chapterItem(index)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := e.getFile(e.resolveHref(item.Href))
+	if err != nil {
+		return "", fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	fragment, err := extractFragment(content, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract fragment %q: %w", id, err)
+	}
+	return fragment, nil
+}
+
+// extractFragment returns the markup of the element with the given id, or of
+// the heading section it starts, as described for GetChapterFragment
+func extractFragment(content []byte, id string) (string, error) {
+	d := newHTMLDecoder(content)
+
+	// open holds the start offset and heading level of every open element
+	type openElement struct {
+		offset int64
+		level  int
+	}
+	var open []openElement
+
+	start := int64(-1)
+	var rootDepth, rootLevel int
+
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			level := headingLevel(t.Name.Local)
+			open = append(open, openElement{offset: offset, level: level})
+
+			if start >= 0 {
+				if len(open) == rootDepth && level > 0 && level <= rootLevel {
+					return string(content[start:offset]), nil
+				}
+				continue
+			}
+			if getAttr(t, "id") != id {
+				continue
+			}
+
+			// Find the heading the element is or lies in
+			for depth := len(open); depth > 0; depth-- {
+				if el := open[depth-1]; el.level > 0 {
+					start, rootDepth, rootLevel = el.offset, depth, el.level
+					break
+				}
+			}
+			if start < 0 {
+				if err := d.Skip(); err != nil && err != io.EOF {
+					return "", err
+				}
+				return string(content[offset:d.InputOffset()]), nil
+			}
+		case xml.EndElement:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+			if start >= 0 && len(open) < rootDepth-1 {
+				return string(content[start:offset]), nil
+			}
+		}
+	}
+
+	if start < 0 {
+		return "", fmt.Errorf("no element with id %q", id)
+	}
+	return string(content[start:]), nil
+}
+
+// headingLevel returns the rank of an <h1> to <h6> element name, or zero for
+// other elements
+func headingLevel(name string) int {
+	name = strings.ToLower(name)
+	if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEpub_GetChapterFragment(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<body><h1 id="top">One</h1><p>Intro</p><h2 id="s1">S1</h2><p>a</p><h3>Sub</h3><p>b</p><h2 id="s2"><a id="anchor"/>S2</h2><p>c</p><h2>End</h2><section><h2 id="s3">S3</h2><p>d</p></section><div id="box"><p>e<br>f</p></div></body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"text/one.xhtml#s1", `<h2 id="s1">S1</h2><p>a</p><h3>Sub</h3><p>b</p>`},
+		{"text/one.xhtml#anchor", `<h2 id="s2"><a id="anchor"/>S2</h2><p>c</p>`},
+		{"text/one.xhtml#s3", `<h2 id="s3">S3</h2><p>d</p>`},
+		{"text/one.xhtml#box", `<div id="box"><p>e<br>f</p></div>`},
+	}
+	for _, tt := range tests {
+		got, err := epub.GetChapterFragment(tt.href)
+		if err != nil {
+			t.Errorf("Failed to get fragment %s: %v", tt.href, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("GetChapterFragment(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+
+	top, err := epub.GetChapterFragment("text/one.xhtml#top")
+	if err != nil {
+		t.Fatalf("Failed to get fragment: %v", err)
+	}
+	if !strings.HasPrefix(top, `<h1 id="top">`) || !strings.HasSuffix(top, `<p>e<br>f</p></div>`) {
+		t.Errorf("Expected top-level heading fragment to run to the end of the body, got %q", top)
+	}
+
+	for _, href := range []string{"text/one.xhtml", "text/one.xhtml#missing", "missing.xhtml#s1"} {
+		if _, err := epub.GetChapterFragment(href); err == nil {
+			t.Errorf("Expected error for %s", href)
+		}
+	}
+}
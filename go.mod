@@ -0,0 +1,8 @@
+module github.com/mszlu521/go-epub
+
+go 1.26.0
+
+require (
+	golang.org/x/net v0.59.0
+	golang.org/x/text v0.42.0
+)
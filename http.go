@@ -0,0 +1,175 @@
+package epub
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// httpReadAhead is the minimum number of bytes fetched by a range request,
+// so that the many small reads made by archive/zip do not each cost a round
+// trip
+const httpReadAhead = 64 << 10
+
+// OpenHTTP creates and parses an EPUB served over HTTP without downloading
+// it
+//
+// The archive is read with HTTP Range requests, so only its central
+// directory and the files that are actually accessed are transferred. This
+// makes it practical to read metadata or single chapters of large books
+// hosted on object storage. The server must support range requests; if it
+// does not, an error is returned. If client is nil, http.DefaultClient is
+// used.
+//
+// ctx applies to every request made for the returned Epub, not just to
+// parsing, so reads fail once it is cancelled. Close releases the idle
+// connections of the client.
+//
+// Example:
+//
+//	e, err := epub.OpenHTTP(ctx, "https://example.com/books/book.epub", nil)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer e.Close()
+//
+//	fmt.Println(e.GetTitle())
+func OpenHTTP(ctx context.Context, url string, client *http.Client, opts ...Option) (*Epub, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	r := &httpReaderAt{ctx: ctx, client: client, url: url}
+	size, err := r.fetchSize()
+	if err != nil {
+		return nil, err
+	}
+
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, zipError(err)
+	}
+
+	epub := newEpub(newZipSource(zipReader), opts)
+	epub.File = zipReader
+	epub.readCloser = r
+
+	if err := epub.parse(ctx); err != nil {
+		if errors.Is(err, ErrDRMProtected) {
+			return epub, err
+		}
+		epub.Close()
+		return nil, err
+	}
+
+	return epub, nil
+}
+
+// httpReaderAt reads a remote file with HTTP Range requests
+//
+// The most recently fetched block is kept, so that sequential reads within
+// httpReadAhead bytes are served without another request.
+type httpReaderAt struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+
+	mu     sync.Mutex
+	offset int64
+	block  []byte
+}
+
+// fetchSize returns the size of the remote file, taken from the
+// Content-Range of a request for its first byte
+func (r *httpReaderAt) fetchSize() (int64, error) {
+	resp, err := r.get(0, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	contentRange := resp.Header.Get("Content-Range")
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("failed to fetch %s: invalid Content-Range %q", r.url, contentRange)
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s: unknown size in Content-Range %q", r.url, contentRange)
+	}
+	return size, nil
+}
+
+// get requests the bytes from first to last, inclusive, and checks that the
+// server answered with the partial content
+func (r *httpReaderAt) get(first, last int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", first, last))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp, nil
+	case http.StatusOK:
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: server does not support range requests", r.url)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s", r.url, resp.Status)
+	}
+}
+
+func (r *httpReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off < r.offset || off+int64(len(p)) > r.offset+int64(len(r.block)) {
+		n := int64(len(p))
+		if n < httpReadAhead {
+			n = httpReadAhead
+		}
+
+		resp, err := r.get(off, off+n-1)
+		if err != nil {
+			return 0, err
+		}
+		block, err := io.ReadAll(io.LimitReader(resp.Body, n))
+		resp.Body.Close()
+		if err != nil {
+			return 0, err
+		}
+		r.offset, r.block = off, block
+	}
+
+	n := copy(p, r.block[off-r.offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close drops the fetched block and closes the idle connections of the
+// client
+func (r *httpReaderAt) Close() error {
+	r.mu.Lock()
+	r.block = nil
+	r.mu.Unlock()
+
+	r.client.CloseIdleConnections()
+	return nil
+}
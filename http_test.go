@@ -0,0 +1,82 @@
+package epub
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingWriter counts the bytes of the response bodies written by a
+// handler
+type countingWriter struct {
+	http.ResponseWriter
+	n *atomic.Int64
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	w.n.Add(int64(len(p)))
+	return w.ResponseWriter.Write(p)
+}
+
+func TestOpenHTTP(t *testing.T) {
+	// An incompressible file that is never read makes up most of the archive
+	large := make([]byte, 4<<20)
+	rand.New(rand.NewSource(1)).Read(large)
+
+	files := testEPUB3Files()
+	files["OEBPS/images/large.bin"] = string(large)
+	data := buildTestZip(t, files)
+
+	var served atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(countingWriter{w, &served}, r, "book.epub", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	epub, err := OpenHTTP(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to open EPUB over HTTP: %v", err)
+	}
+	defer epub.Close()
+
+	content, err := epub.GetChapterContent(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+	if !strings.Contains(content, "<p>Text</p>") {
+		t.Errorf("Unexpected chapter content: %q", content)
+	}
+
+	if n := served.Load(); n >= int64(len(data))/2 {
+		t.Errorf("Expected only part of the %d byte archive to be transferred, got %d bytes", len(data), n)
+	}
+
+	if err := epub.Close(); err != nil {
+		t.Errorf("Failed to close EPUB: %v", err)
+	}
+}
+
+func TestOpenHTTP_NoRangeSupport(t *testing.T) {
+	data := buildTestZip(t, testEPUB3Files())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	_, err := OpenHTTP(context.Background(), server.URL, server.Client())
+	if err == nil || !strings.Contains(err.Error(), "range requests") {
+		t.Errorf("Expected an error about range requests, got %v", err)
+	}
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	if _, err := OpenHTTP(context.Background(), notFound.URL, nil); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,76 @@
+package epub
+
+import (
+	"strings"
+)
+
+// Layouts of EPUB 3 fixed-layout rendering
+const (
+	LayoutReflowable   = "reflowable"
+	LayoutPrePaginated = "pre-paginated"
+)
+
+// layout returns the book-wide rendition:layout, defaulting to reflowable
+func (e *Epub) layout() string {
+	if layout := e.Metadata.property("rendition:layout"); layout == LayoutPrePaginated {
+		return layout
+	}
+	return LayoutReflowable
+}
+
+// IsFixedLayout reports whether the book is a fixed-layout publication
+//
+// A book is fixed-layout when its package metadata declares
+// <meta property="rendition:layout">pre-paginated</meta>. Individual chapters
+// may still override the layout; use ChapterLayout to find the layout of a
+// specific chapter.
+func (e *Epub) IsFixedLayout() bool {
+	return e.layout() == LayoutPrePaginated
+}
+
+// ChapterLayout returns the layout of the chapter at the given spine index
+//
+// The result is LayoutPrePaginated or LayoutReflowable. A
+// rendition:layout-pre-paginated or rendition:layout-reflowable property on
+// the spine item overrides the book-wide layout. The index is zero-based, as
+// for GetChapterContent; an empty string is returned if it is out of range.
+func (e *Epub) ChapterLayout(chapterIndex int) string {
+	if chapterIndex < 0 || chapterIndex >= len(e.Spine) {
+		return ""
+	}
+
+	for _, prop := range strings.Fields(e.Spine[chapterIndex].Properties) {
+		switch prop {
+		case "rendition:layout-pre-paginated":
+			return LayoutPrePaginated
+		case "rendition:layout-reflowable":
+			return LayoutReflowable
+		}
+	}
+
+	return e.layout()
+}
+
+// Reading directions returned by ReadingDirection
+const (
+	DirectionLTR     = "ltr"
+	DirectionRTL     = "rtl"
+	DirectionDefault = "default"
+)
+
+// ReadingDirection returns the page progression direction of the book
+//
+// The result is taken from the page-progression-direction attribute of the
+// spine: DirectionRTL for books read right to left, such as Arabic, Hebrew
+// or vertically set Japanese, DirectionLTR for left to right, and
+// DirectionDefault if the attribute is missing or has another value, in
+// which case the reading system chooses the direction.
+func (e *Epub) ReadingDirection() string {
+	switch strings.ToLower(e.pageDirection) {
+	case DirectionLTR:
+		return DirectionLTR
+	case DirectionRTL:
+		return DirectionRTL
+	}
+	return DirectionDefault
+}
@@ -0,0 +1,68 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEpub_IsFixedLayout(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+	if epub.IsFixedLayout() {
+		t.Error("Expected book without rendition:layout to be reflowable")
+	}
+	if layout := epub.ChapterLayout(0); layout != LayoutReflowable {
+		t.Errorf("Expected reflowable chapter, got %q", layout)
+	}
+
+	files := testEPUB3Files()
+	opf := strings.Replace(testNavOPF, `</metadata>`, `	<meta property="rendition:layout">pre-paginated</meta>
+	</metadata>`, 1)
+	files["OEBPS/content.opf"] = strings.Replace(opf, `<itemref idref="c2"/>`, `<itemref idref="c2" properties="page-spread-left rendition:layout-reflowable"/>`, 1)
+	epub = newTestEpub(t, files)
+
+	if !epub.IsFixedLayout() {
+		t.Error("Expected book to be fixed-layout")
+	}
+	if layout := epub.ChapterLayout(0); layout != LayoutPrePaginated {
+		t.Errorf("Expected first chapter to inherit the book layout, got %q", layout)
+	}
+	if layout := epub.ChapterLayout(1); layout != LayoutReflowable {
+		t.Errorf("Expected spine override for second chapter, got %q", layout)
+	}
+	if layout := epub.ChapterLayout(5); layout != "" {
+		t.Errorf("Expected empty layout for invalid index, got %q", layout)
+	}
+}
+
+const testRTLOPF = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+	<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:identifier id="uid">urn:uuid:5678</dc:identifier>
+		<dc:title>كتاب</dc:title>
+		<dc:language>ar</dc:language>
+	</metadata>
+	<manifest>
+		<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+		<item id="c1" href="text/one.xhtml" media-type="application/xhtml+xml"/>
+	</manifest>
+	<spine page-progression-direction="rtl">
+		<itemref idref="c1"/>
+	</spine>
+</package>`
+
+func TestEpub_ReadingDirection(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = testRTLOPF
+	if dir := newTestEpub(t, files).ReadingDirection(); dir != DirectionRTL {
+		t.Errorf("Expected rtl reading direction, got %q", dir)
+	}
+
+	files["OEBPS/content.opf"] = strings.Replace(testRTLOPF, `"rtl"`, `"ltr"`, 1)
+	if dir := newTestEpub(t, files).ReadingDirection(); dir != DirectionLTR {
+		t.Errorf("Expected ltr reading direction, got %q", dir)
+	}
+
+	if dir := newTestEpub(t, testEPUB3Files()).ReadingDirection(); dir != DirectionDefault {
+		t.Errorf("Expected default reading direction, got %q", dir)
+	}
+}
@@ -0,0 +1,263 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Link describes an <a href> hyperlink found in a chapter
+type Link struct {
+	// SourceChapter is the spine index of the chapter containing the link
+	SourceChapter int
+
+	// Href is the reference as written in the chapter
+	Href string
+
+	// TargetExists reports whether the target file, and the element named
+	// by the fragment if any, exist in the EPUB. It is always false for
+	// external links.
+	TargetExists bool
+
+	// TargetSpineIndex is the spine index of the target document, or -1 if
+	// it is not part of the spine or does not exist
+	TargetSpineIndex int
+
+	// External reports whether the link points outside the EPUB, e.g. to a
+	// website or a mailto address
+	External bool
+}
+
+// InternalLinks returns every hyperlink of the book and whether its target
+// resolves
+//
+// All <a href> elements of the HTML documents in the spine are reported in
+// reading order. Each reference is resolved relative to its chapter, and a
+// "#fragment" must match the id of an element in the target document for the
+// target to exist, so both missing files and dangling anchors are found.
+// Fragment-only references point into the chapter itself. External URLs are
+// reported with External set and are not checked.
+//
+// Example:
+//
+//	links, err := e.InternalLinks()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, link := range links {
+//		if !link.External && !link.TargetExists {
+//			fmt.Printf("chapter %d: broken link %s\n", link.SourceChapter, link.Href)
+//		}
+//	}
+func (e *Epub) InternalLinks() ([]Link, error) {
+	// spineIndex maps the names of the spine documents to their index
+	spineIndex := make(map[string]int)
+	for i, itemRef := range e.Spine {
+		item := e.findItemByID(itemRef.IDRef)
+		if item == nil {
+			continue
+		}
+		if name, ok := e.findFile(e.resolveHref(item.Href)); ok {
+			if _, dup := spineIndex[name]; !dup {
+				spineIndex[name] = i
+			}
+		}
+	}
+
+	// ids caches the element ids of the documents that links point into
+	ids := make(map[string]map[string]bool)
+	targetIDs := func(name string) map[string]bool {
+		if set, ok := ids[name]; ok {
+			return set
+		}
+		set := make(map[string]bool)
+		if content, err := e.getFile(name); err == nil {
+			// A target that cannot be parsed has no addressable elements
+			_, set, _ = scanLinks(content)
+		}
+		ids[name] = set
+		return set
+	}
+
+	var links []Link
+	for i, itemRef := range e.Spine {
+		item := e.findItemByID(itemRef.IDRef)
+		if item == nil || !strings.Contains(item.MediaType, "html") {
+			continue
+		}
+
+		chapterPath := e.resolveHref(item.Href)
+		content, err := e.getFile(chapterPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chapter content: %w", err)
+		}
+
+		hrefs, chapterIDs, err := scanLinks(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse chapter %d: %w", i, err)
+		}
+		chapterName, _ := e.findFile(chapterPath)
+		ids[chapterName] = chapterIDs
+
+		for _, href := range hrefs {
+			link := Link{SourceChapter: i, Href: href, TargetSpineIndex: -1}
+			if isExternalRef(href) {
+				link.External = true
+				links = append(links, link)
+				continue
+			}
+
+			name := chapterName
+			if resolved := resolveRef(chapterPath, href); resolved != "" {
+				var ok bool
+				if name, ok = e.findFile(resolved); !ok {
+					links = append(links, link)
+					continue
+				}
+			}
+
+			if index, ok := spineIndex[name]; ok {
+				link.TargetSpineIndex = index
+			}
+			link.TargetExists = true
+			if j := strings.Index(href, "#"); j >= 0 {
+				if fragment := unescapeHref(href[j+1:]); fragment != "" {
+					link.TargetExists = targetIDs(name)[fragment]
+				}
+			}
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// scanLinks returns the non-empty href attributes of the <a> elements of an
+// (X)HTML document and the set of element ids it defines
+func scanLinks(content []byte) ([]string, map[string]bool, error) {
+	var hrefs []string
+	ids := make(map[string]bool)
+
+	d := newHTMLDecoder(content)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return hrefs, ids, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if id := getAttr(start, "id"); id != "" {
+			ids[id] = true
+		}
+		if strings.EqualFold(start.Name.Local, "a") {
+			if href := strings.TrimSpace(getAttr(start, "href")); href != "" {
+				hrefs = append(hrefs, href)
+			}
+		}
+	}
+}
+
+// BrokenResources returns the images, stylesheets and scripts that are
+// referenced by the content documents but missing from the EPUB
+//
+// Every (X)HTML document in the manifest is scanned for <img src>, SVG
+// <image href>, <link rel="stylesheet" href> and <script src> references.
+// Each reference is resolved relative to its document, and the paths that
+// do not exist are returned relative to the root of the EPUB, each listed
+// only once in the order they were found. External URLs and data URIs are
+// not checked. Documents that are themselves missing are skipped; they are
+// reported by Validate.
+//
+// Example:
+//
+//	missing, err := e.BrokenResources()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, path := range missing {
+//		fmt.Println("missing resource:", path)
+//	}
+func (e *Epub) BrokenResources() ([]string, error) {
+	var missing []string
+	seen := make(map[string]bool)
+
+	for i := range e.Manifest {
+		item := &e.Manifest[i]
+		if !strings.Contains(itemMediaType(item), "html") {
+			continue
+		}
+
+		docPath := e.resolveHref(item.Href)
+		if _, ok := e.findFile(docPath); !ok {
+			continue
+		}
+		content, err := e.getFile(docPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", docPath, err)
+		}
+
+		refs, err := scanResourceRefs(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", docPath, err)
+		}
+		for _, ref := range refs {
+			if isExternalRef(ref) {
+				continue
+			}
+			resolved := resolveRef(docPath, ref)
+			if resolved == "" || seen[resolved] {
+				continue
+			}
+			seen[resolved] = true
+			if _, ok := e.findFile(resolved); !ok {
+				missing = append(missing, resolved)
+			}
+		}
+	}
+
+	return missing, nil
+}
+
+// scanResourceRefs returns the image, stylesheet and script references of
+// an (X)HTML document in document order
+func scanResourceRefs(content []byte) ([]string, error) {
+	var refs []string
+
+	d := newHTMLDecoder(content)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return refs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var ref string
+		switch strings.ToLower(start.Name.Local) {
+		case "img", "script":
+			ref = getAttr(start, "src")
+		case "image":
+			ref = getAttr(start, "href")
+		case "link":
+			if isStylesheetLink(start) {
+				ref = getAttr(start, "href")
+			}
+		}
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+}
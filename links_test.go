@@ -0,0 +1,80 @@
+package epub
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEpub_InternalLinks(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<body>
+	<h1 id="top">One</h1>
+	<a href="two.xhtml#s1">next section</a>
+	<a href="two.xhtml#missing">dangling anchor</a>
+	<a href="#top">top</a>
+	<a href="three.xhtml">missing file</a>
+	<a href="../nav.xhtml">contents</a>
+	<a href="https://example.com/">website</a>
+	<a>no href</a>
+</body>
+</html>`
+	files["OEBPS/text/two.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<body>
+	<section id="s1"><a href="one.xhtml">back</a></section>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	links, err := epub.InternalLinks()
+	if err != nil {
+		t.Fatalf("Failed to get links: %v", err)
+	}
+
+	want := []Link{
+		{SourceChapter: 0, Href: "two.xhtml#s1", TargetExists: true, TargetSpineIndex: 1},
+		{SourceChapter: 0, Href: "two.xhtml#missing", TargetExists: false, TargetSpineIndex: 1},
+		{SourceChapter: 0, Href: "#top", TargetExists: true, TargetSpineIndex: 0},
+		{SourceChapter: 0, Href: "three.xhtml", TargetExists: false, TargetSpineIndex: -1},
+		{SourceChapter: 0, Href: "../nav.xhtml", TargetExists: true, TargetSpineIndex: -1},
+		{SourceChapter: 0, Href: "https://example.com/", TargetSpineIndex: -1, External: true},
+		{SourceChapter: 1, Href: "one.xhtml", TargetExists: true, TargetSpineIndex: 0},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("Unexpected links:\ngot  %+v\nwant %+v", links, want)
+	}
+}
+
+func TestEpub_BrokenResources(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:xlink="http://www.w3.org/1999/xlink">
+<head>
+	<link rel="stylesheet" href="../css/style.css"/>
+	<link rel="stylesheet" href="../css/missing.css"/>
+	<link rel="next" href="../nowhere.xhtml"/>
+	<script src="../js/missing.js"></script>
+</head>
+<body>
+	<img src="../images/a.png"/>
+	<img src="../images/missing.png"/>
+	<img src="https://example.com/remote.png"/>
+	<svg><image xlink:href="../images/missing.png"/></svg>
+</body>
+</html>`
+	files["OEBPS/css/style.css"] = "p { margin: 0; }"
+	files["OEBPS/images/a.png"] = "png"
+	epub := newTestEpub(t, files)
+
+	missing, err := epub.BrokenResources()
+	if err != nil {
+		t.Fatalf("Failed to get broken resources: %v", err)
+	}
+
+	want := []string{"OEBPS/css/missing.css", "OEBPS/js/missing.js", "OEBPS/images/missing.png"}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("Unexpected broken resources: got %v, want %v", missing, want)
+	}
+}
@@ -0,0 +1,247 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// markdownBlankLine and markdownBlankLines match whitespace-only lines and
+// runs of blank lines in converted Markdown
+var (
+	markdownBlankLine  = regexp.MustCompile(`\n[ \t]+\n`)
+	markdownBlankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// ChapterToMarkdown returns the content of a chapter converted to Markdown
+//
+// Headings, paragraphs, bold and italic text, inline code, preformatted
+// blocks, ordered and unordered lists, blockquotes, horizontal rules, links
+// and images are converted to their Markdown equivalents. Other elements are
+// reduced to their text content, and the content of <head>, <script> and
+// <style> elements is dropped. Relative link and image targets are resolved
+// relative to the chapter file, so they are zip paths that a later step can
+// rewrite as needed; external URLs and fragment-only links are kept as they
+// are. The index is zero-based, as for GetChapterContent.
+//
+// Example:
+//
+//	md, err := e.ChapterToMarkdown(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(md)
+func (e *Epub) ChapterToMarkdown(chapterIndex int) (string, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return "", err
+	}
+
+	chapterPath := e.resolveHref(item.Href)
+	content, err := e.getFile(chapterPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	c := markdownConverter{d: newHTMLDecoder(content), docPath: chapterPath}
+	md, err := c.children()
+	if err != nil {
+		return "", fmt.Errorf("failed to convert chapter: %w", err)
+	}
+
+	md = markdownBlankLine.ReplaceAllString(md, "\n\n")
+	md = markdownBlankLines.ReplaceAllString(md, "\n\n")
+	return strings.TrimSpace(md), nil
+}
+
+// markdownConverter converts the tokens of an (X)HTML document to Markdown
+type markdownConverter struct {
+	d       *xml.Decoder
+	docPath string
+}
+
+// children converts the content of the current element up to and including
+// its closing tag, or up to the end of the document
+func (c *markdownConverter) children() (string, error) {
+	var sb strings.Builder
+
+	for {
+		tok, err := c.d.Token()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			md, err := c.element(t)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(md)
+		case xml.EndElement:
+			return sb.String(), nil
+		case xml.CharData:
+			sb.WriteString(collapseSpace(string(t)))
+		}
+	}
+}
+
+// element converts an element whose start tag has just been read
+func (c *markdownConverter) element(start xml.StartElement) (string, error) {
+	name := strings.ToLower(start.Name.Local)
+
+	switch name {
+	case "br":
+		return "  \n", c.d.Skip()
+	case "hr":
+		return "\n\n---\n\n", c.d.Skip()
+	case "img", "image":
+		src := getAttr(start, "src")
+		if name == "image" {
+			src = getAttr(start, "href")
+		}
+		alt := collapseSpace(getAttr(start, "alt"))
+		return "![" + strings.TrimSpace(alt) + "](" + resolveLink(c.docPath, src, "") + ")", c.d.Skip()
+	case "pre":
+		code, err := collectRawText(c.d)
+		if err != nil {
+			return "", err
+		}
+		return "\n\n```\n" + strings.Trim(code, "\n") + "\n```\n\n", nil
+	case "ul", "ol":
+		return c.list(name == "ol")
+	}
+
+	if skippedElements[name] {
+		return "", c.d.Skip()
+	}
+
+	inner, err := c.children()
+	if err != nil {
+		return "", err
+	}
+
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(name[1] - '0')
+		heading := strings.Join(strings.Fields(inner), " ")
+		return "\n\n" + strings.Repeat("#", level) + " " + heading + "\n\n", nil
+	case "blockquote":
+		lines := strings.Split(strings.TrimSpace(markdownBlankLines.ReplaceAllString(inner, "\n\n")), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+strings.TrimSpace(line), " ")
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n", nil
+	case "strong", "b":
+		return wrapInline(inner, "**"), nil
+	case "em", "i":
+		return wrapInline(inner, "*"), nil
+	case "code":
+		return wrapInline(inner, "`"), nil
+	case "a":
+		href := getAttr(start, "href")
+		if href == "" {
+			return inner, nil
+		}
+		return "[" + strings.TrimSpace(inner) + "](" + resolveLink(c.docPath, href, "") + ")", nil
+	case "td", "th":
+		return inner + " ", nil
+	}
+
+	if blockElements[name] {
+		return "\n\n" + strings.TrimSpace(inner) + "\n\n", nil
+	}
+	return inner, nil
+}
+
+// list converts the <li> items of a list up to its closing tag
+//
+// Continuation lines of an item, including nested lists, are indented to
+// line up with the item's text.
+func (c *markdownConverter) list(ordered bool) (string, error) {
+	var items []string
+
+	for {
+		tok, err := c.d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := tok.(xml.EndElement); ok {
+			break
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(start.Name.Local, "li") {
+			if err := c.d.Skip(); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		inner, err := c.children()
+		if err != nil {
+			return "", err
+		}
+
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", len(items)+1)
+		}
+
+		inner = markdownBlankLine.ReplaceAllString(inner, "\n")
+		lines := strings.Split(strings.TrimSpace(markdownBlankLines.ReplaceAllString(inner, "\n")), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+		items = append(items, marker+indentLines(strings.Join(compactStrings(lines), "\n"), len(marker)))
+	}
+
+	return "\n\n" + strings.Join(items, "\n") + "\n\n", nil
+}
+
+// collapseSpace replaces every run of whitespace in s with a single space
+func collapseSpace(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		sb.WriteRune(r)
+		space = false
+	}
+	return sb.String()
+}
+
+// wrapInline wraps inline content in a Markdown delimiter, keeping leading
+// and trailing spaces outside of it
+func wrapInline(s, delim string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+
+	lead := s[:strings.Index(s, trimmed)]
+	trail := s[len(lead)+len(trimmed):]
+	return lead + delim + trimmed + delim + trail
+}
+
+// indentLines indents all lines of s but the first by n spaces
+func indentLines(s string, n int) string {
+	return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", n))
+}
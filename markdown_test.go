@@ -0,0 +1,53 @@
+package epub
+
+import "testing"
+
+func TestEpub_ChapterToMarkdown(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>One</title><style>p { color: red; }</style></head>
+<body>
+	<h1>Chapter
+		One</h1>
+	<p>Some <strong>bold</strong>, <em>italic </em>and <code>code</code> text.<br/>Next line.</p>
+	<ul>
+		<li>First</li>
+		<li>Second
+			<ol><li>Nested</li></ol>
+		</li>
+	</ul>
+	<blockquote><p>Quoted</p><p>twice</p></blockquote>
+	<p>See <a href="two.xhtml#s1">two</a>, <a href="#top">the top</a> and <a href="https://example.com/">the web</a>.</p>
+	<p><img src="../images/pic.png" alt="A picture"/></p>
+	<pre>line 1
+  line 2</pre>
+	<hr/>
+	<div><span>Plain</span> <unknown>text</unknown></div>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	md, err := epub.ChapterToMarkdown(0)
+	if err != nil {
+		t.Fatalf("Failed to convert chapter: %v", err)
+	}
+
+	want := "# Chapter One\n\n" +
+		"Some **bold**, *italic* and `code` text.  \nNext line.\n\n" +
+		"- First\n" +
+		"- Second\n  1. Nested\n\n" +
+		"> Quoted\n>\n> twice\n\n" +
+		"See [two](OEBPS/text/two.xhtml#s1), [the top](#top) and [the web](https://example.com/).\n\n" +
+		"![A picture](OEBPS/images/pic.png)\n\n" +
+		"```\nline 1\n  line 2\n```\n\n" +
+		"---\n\n" +
+		"Plain text"
+	if md != want {
+		t.Errorf("Unexpected Markdown:\n%s\nwant:\n%s", md, want)
+	}
+
+	if _, err := epub.ChapterToMarkdown(99); err == nil {
+		t.Error("Expected error for out of range chapter")
+	}
+}
@@ -0,0 +1,202 @@
+package epub
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaOverlay represents an EPUB 3 SMIL media overlay
+//
+// A media overlay synchronizes the elements of a content document with clips
+// of recorded audio, so that the text can be highlighted while it is read
+// aloud. Path is the zip path of the SMIL file. Clips are listed in playback
+// order.
+type MediaOverlay struct {
+	Path  string
+	Clips []OverlayClip
+}
+
+// OverlayClip represents a text element and the audio clip narrating it
+//
+// TextSrc is the zip path of the content document and TextID the ID of the
+// element inside it. AudioSrc is the zip path of the audio file, and Begin and
+// End delimit the clip within that file. End is zero if the clip runs to the
+// end of the audio file.
+type OverlayClip struct {
+	TextSrc  string
+	TextID   string
+	AudioSrc string
+	Begin    time.Duration
+	End      time.Duration
+}
+
+// Clip returns the clip narrating the text element with the given ID
+func (m *MediaOverlay) Clip(id string) (OverlayClip, bool) {
+	for _, clip := range m.Clips {
+		if clip.TextID == id {
+			return clip, true
+		}
+	}
+	return OverlayClip{}, false
+}
+
+// smilPar is a <par> element of a SMIL document
+type smilPar struct {
+	Text struct {
+		Src string `xml:"src,attr"`
+	} `xml:"text"`
+	Audio struct {
+		Src       string `xml:"src,attr"`
+		ClipBegin string `xml:"clipBegin,attr"`
+		ClipEnd   string `xml:"clipEnd,attr"`
+	} `xml:"audio"`
+}
+
+// GetMediaOverlay returns the media overlay of a specific chapter
+//
+// This method parses the SMIL file referenced by the media-overlay attribute
+// of the chapter's manifest item. Text and audio references are resolved
+// relative to the SMIL file, so they can be passed directly to
+// GetFileReader. The index is zero-based, as for GetChapterContent. If the
+// chapter has no media overlay, an error is returned.
+//
+// Example:
+//
+//	overlay, err := e.GetMediaOverlay(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, clip := range overlay.Clips {
+//		fmt.Printf("#%s: %s [%v-%v]\n", clip.TextID, clip.AudioSrc, clip.Begin, clip.End)
+//	}
+func (e *Epub) GetMediaOverlay(chapterIndex int) (*MediaOverlay, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.MediaOverlay == "" {
+		return nil, fmt.Errorf("chapter %d has no media overlay", chapterIndex)
+	}
+	smilItem := e.findItemByID(item.MediaOverlay)
+	if smilItem == nil {
+		return nil, fmt.Errorf("media overlay item not found: %s", item.MediaOverlay)
+	}
+
+	smilPath := e.resolveHref(smilItem.Href)
+	data, err := e.getFile(smilPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media overlay: %w", err)
+	}
+
+	clips, err := parseSMIL(data, smilPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse media overlay: %w", err)
+	}
+
+	return &MediaOverlay{Path: smilPath, Clips: clips}, nil
+}
+
+// parseSMIL collects the clips of every <par> element of a SMIL document,
+// however deeply nested in <seq> elements, in document order
+func parseSMIL(data []byte, smilPath string) ([]OverlayClip, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var clips []OverlayClip
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return clips, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "par" {
+			continue
+		}
+
+		var par smilPar
+		if err := d.DecodeElement(&par, &start); err != nil {
+			return nil, err
+		}
+
+		clip := OverlayClip{
+			TextSrc:  resolveRef(smilPath, par.Text.Src),
+			AudioSrc: resolveRef(smilPath, par.Audio.Src),
+		}
+		if i := strings.Index(par.Text.Src, "#"); i >= 0 {
+			clip.TextID = par.Text.Src[i+1:]
+		}
+		if clip.Begin, err = parseClockValue(par.Audio.ClipBegin); err != nil {
+			return nil, err
+		}
+		if clip.End, err = parseClockValue(par.Audio.ClipEnd); err != nil {
+			return nil, err
+		}
+
+		clips = append(clips, clip)
+	}
+}
+
+// clockUnits maps the metric suffixes of SMIL timecount values to durations
+var clockUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	// "ms" and "min" must be tried before "s" and "m"
+	{"ms", time.Millisecond},
+	{"min", time.Minute},
+	{"h", time.Hour},
+	{"s", time.Second},
+}
+
+// parseClockValue parses a SMIL clock value
+//
+// Full ("1:02:03.5") and partial ("02:03.5") clock values as well as timecount
+// values with an optional metric suffix ("3.5s", "300ms", "2min", "1h") are
+// supported. Timecount values without a suffix are in seconds. An empty value
+// yields zero.
+func parseClockValue(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("invalid clock value: %s", value)
+		}
+
+		var total float64
+		for _, part := range parts {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid clock value: %s", value)
+			}
+			total = total*60 + n
+		}
+		return time.Duration(total * float64(time.Second)), nil
+	}
+
+	number, unit := value, time.Second
+	for _, u := range clockUnits {
+		if strings.HasSuffix(value, u.suffix) {
+			number, unit = strings.TrimSuffix(value, u.suffix), u.unit
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid clock value: %s", value)
+	}
+	return time.Duration(n * float64(unit)), nil
+}
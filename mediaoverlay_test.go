@@ -0,0 +1,91 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSMIL = `<?xml version="1.0" encoding="UTF-8"?>
+<smil xmlns="http://www.w3.org/ns/SMIL" xmlns:epub="http://www.idpf.org/2007/ops" version="3.0">
+	<body>
+		<seq epub:textref="../text/one.xhtml" epub:type="chapter">
+			<par id="p1">
+				<text src="../text/one.xhtml#w1"/>
+				<audio src="../audio/one.mp3" clipBegin="0:00:00.000" clipEnd="0:00:01.500"/>
+			</par>
+			<seq epub:textref="../text/one.xhtml#sec">
+				<par id="p2">
+					<text src="../text/one.xhtml#w2"/>
+					<audio src="../audio/one.mp3" clipBegin="1.5s" clipEnd="3200ms"/>
+				</par>
+			</seq>
+		</seq>
+	</body>
+</smil>`
+
+func TestEpub_GetMediaOverlay(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(
+		strings.Replace(testNavOPF, `media-type="application/xhtml+xml"/>`, `media-type="application/xhtml+xml" media-overlay="mo1"/>`, 1),
+		`</manifest>`, `	<item id="mo1" href="smil/one.smil" media-type="application/smil+xml"/>
+	</manifest>`, 1)
+	files["OEBPS/smil/one.smil"] = testSMIL
+	epub := newTestEpub(t, files)
+
+	overlay, err := epub.GetMediaOverlay(0)
+	if err != nil {
+		t.Fatalf("Failed to get media overlay: %v", err)
+	}
+
+	if overlay.Path != "OEBPS/smil/one.smil" {
+		t.Errorf("Expected overlay path OEBPS/smil/one.smil, got %q", overlay.Path)
+	}
+
+	want := []OverlayClip{
+		{TextSrc: "OEBPS/text/one.xhtml", TextID: "w1", AudioSrc: "OEBPS/audio/one.mp3", Begin: 0, End: 1500 * time.Millisecond},
+		{TextSrc: "OEBPS/text/one.xhtml", TextID: "w2", AudioSrc: "OEBPS/audio/one.mp3", Begin: 1500 * time.Millisecond, End: 3200 * time.Millisecond},
+	}
+	if len(overlay.Clips) != len(want) {
+		t.Fatalf("Expected %d clips, got %+v", len(want), overlay.Clips)
+	}
+	for i := range want {
+		if overlay.Clips[i] != want[i] {
+			t.Errorf("Clip %d: got %+v, want %+v", i, overlay.Clips[i], want[i])
+		}
+	}
+
+	if clip, ok := overlay.Clip("w2"); !ok || clip.Begin != 1500*time.Millisecond {
+		t.Errorf("Expected clip for w2, got %+v (%v)", clip, ok)
+	}
+
+	if _, err := epub.GetMediaOverlay(1); err == nil {
+		t.Error("Expected error for chapter without media overlay, got nil")
+	}
+}
+
+func TestParseClockValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"1:02:03.5", time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"02:03", 2*time.Minute + 3*time.Second},
+		{"3.5s", 3500 * time.Millisecond},
+		{"300ms", 300 * time.Millisecond},
+		{"2min", 2 * time.Minute},
+		{"1h", time.Hour},
+		{"12", 12 * time.Second},
+	}
+	for _, tt := range tests {
+		got, err := parseClockValue(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("parseClockValue(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseClockValue("soon"); err == nil {
+		t.Error("Expected error for invalid clock value, got nil")
+	}
+}
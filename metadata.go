@@ -0,0 +1,760 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Refinements holds the EPUB 3 refinements of a metadata element, keyed by
+// property
+//
+// Refinements are <meta refines="#id" property="..."> elements that add
+// details such as title-type, display-seq, file-as or alternate-script to the
+// element with the given id. A property may be refined more than once, so
+// every value is kept in document order.
+type Refinements map[string][]string
+
+// Get returns the first value of the given refinement property, or an empty
+// string if the element has no such refinement
+func (r Refinements) Get(property string) string {
+	if values := r[property]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Title represents a <dc:title> element of the book
+type Title struct {
+	ID          string      `xml:"id,attr"`
+	Value       string      `xml:",chardata"`
+	Refinements Refinements `xml:"-"`
+}
+
+// Creator represents a creator of the book, such as an author or translator
+type Creator struct {
+	ID          string      `xml:"id,attr"`
+	Name        string      `xml:",chardata"`
+	Role        string      `xml:"role,attr"`
+	FileAs      string      `xml:"file-as,attr"`
+	Refinements Refinements `xml:"-"`
+}
+
+// Identifier represents an identifier of the book, such as a UUID or ISBN
+type Identifier struct {
+	ID          string      `xml:"id,attr"`
+	Scheme      string      `xml:"scheme,attr"`
+	Value       string      `xml:",chardata"`
+	Refinements Refinements `xml:"-"`
+}
+
+// Date represents a date of the book
+//
+// In EPUB 2 a book may declare several dates, distinguished by their
+// opf:event attribute (e.g. "publication", "creation" or "modification").
+// EPUB 3 books carry a single publication date without an event.
+type Date struct {
+	Event string `xml:"event,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Meta represents a <meta> element of the package metadata
+//
+// EPUB 2 meta elements carry their data in the Name and Content attributes,
+// while EPUB 3 meta elements use Property and the element text (Value).
+// Refines holds the id of the element being refined, including the leading
+// "#".
+type Meta struct {
+	ID       string `xml:"id,attr"`
+	Name     string `xml:"name,attr"`
+	Content  string `xml:"content,attr"`
+	Property string `xml:"property,attr"`
+	Refines  string `xml:"refines,attr"`
+	Scheme   string `xml:"scheme,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// normalize post-processes the unmarshalled metadata, filling in the single
+// value fields from their repeatable counterparts
+func (m *Metadata) normalize(uniqueID string) {
+	m.normalizeTitles()
+	m.normalizeCreators()
+	m.normalizeIdentifiers(uniqueID)
+	m.normalizeDates()
+
+	m.Subjects = compactStrings(m.Subjects)
+	if len(m.Subjects) > 0 {
+		m.Subject = m.Subjects[0]
+	}
+
+	m.Languages = compactStrings(m.Languages)
+	if len(m.Languages) > 0 {
+		m.Language = m.Languages[0]
+	}
+
+	m.Publishers = compactStrings(m.Publishers)
+	if len(m.Publishers) > 0 {
+		m.Publisher = m.Publishers[0]
+	}
+
+	m.Sources = compactStrings(m.Sources)
+	m.Relations = compactStrings(m.Relations)
+}
+
+// refinements returns the values of all meta elements refining the element
+// with the given id using the given property
+func (m *Metadata) refinements(id, property string) []string {
+	if id == "" {
+		return nil
+	}
+
+	var values []string
+	for _, meta := range m.Meta {
+		if strings.TrimPrefix(meta.Refines, "#") == id && meta.Property == property {
+			values = append(values, strings.TrimSpace(meta.Value))
+		}
+	}
+	return values
+}
+
+// refinement returns the first value refining the element with the given id
+// using the given property, or an empty string if there is none
+func (m *Metadata) refinement(id, property string) string {
+	if values := m.refinements(id, property); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// refinementsOf collects all meta elements refining the element with the
+// given id, or returns nil if there are none
+func (m *Metadata) refinementsOf(id string) Refinements {
+	if id == "" {
+		return nil
+	}
+
+	var refinements Refinements
+	for _, meta := range m.Meta {
+		if strings.TrimPrefix(meta.Refines, "#") != id || meta.Property == "" {
+			continue
+		}
+		if refinements == nil {
+			refinements = make(Refinements)
+		}
+		refinements[meta.Property] = append(refinements[meta.Property], strings.TrimSpace(meta.Value))
+	}
+	return refinements
+}
+
+// property returns the value of the first EPUB 3 meta element with the given
+// property that does not refine another element, or an empty string
+func (m *Metadata) property(name string) string {
+	for _, meta := range m.Meta {
+		if meta.Property == name && meta.Refines == "" {
+			return strings.TrimSpace(meta.Value)
+		}
+	}
+	return ""
+}
+
+// normalizeTitles trims title values, attaches their EPUB 3 refinements and
+// sets the single Title field to the main title: the first title refined with
+// title-type "main", or the first title if none is
+func (m *Metadata) normalizeTitles() {
+	for i := range m.Titles {
+		title := &m.Titles[i]
+		title.Value = strings.TrimSpace(title.Value)
+		title.Refinements = m.refinementsOf(title.ID)
+	}
+
+	for _, title := range m.Titles {
+		if title.Refinements.Get("title-type") == "main" {
+			m.Title = title.Value
+			return
+		}
+	}
+	if len(m.Titles) > 0 {
+		m.Title = m.Titles[0].Value
+	}
+}
+
+// TitledEntry represents a title of the book together with its kind
+//
+// Type is the EPUB 3 title-type refinement of the title, such as "main",
+// "subtitle", "short", "collection", "edition" or "expanded", and is empty if
+// the title does not declare one.
+type TitledEntry struct {
+	Value string
+	Type  string
+}
+
+// GetTitles returns all titles of the book
+//
+// Titles refined with a display-seq are returned first, in the order it
+// gives, followed by the remaining titles in document order. Combined with
+// their types, this lets a caller display a full title such as
+// "Main Title: Subtitle".
+//
+// Example:
+//
+//	for _, title := range e.GetTitles() {
+//		if title.Type == "subtitle" {
+//			fmt.Println("Subtitle:", title.Value)
+//		}
+//	}
+func (e *Epub) GetTitles() []TitledEntry {
+	titles := make([]Title, 0, len(e.Metadata.Titles))
+	for _, title := range e.Metadata.Titles {
+		if title.Value != "" {
+			titles = append(titles, title)
+		}
+	}
+
+	// displaySeq returns the display-seq of a title, ordering titles without
+	// one after all others
+	displaySeq := func(title Title) int {
+		if seq, err := strconv.Atoi(title.Refinements.Get("display-seq")); err == nil {
+			return seq
+		}
+		return math.MaxInt
+	}
+	sort.SliceStable(titles, func(i, j int) bool {
+		return displaySeq(titles[i]) < displaySeq(titles[j])
+	})
+
+	var entries []TitledEntry
+	for _, title := range titles {
+		entries = append(entries, TitledEntry{
+			Value: title.Value,
+			Type:  title.Refinements.Get("title-type"),
+		})
+	}
+	return entries
+}
+
+// normalizeCreators trims creator and contributor names, applies EPUB 3
+// role and file-as refinements and sets the single Creator and Contributor
+// fields to the first name of each list
+func (m *Metadata) normalizeCreators() {
+	m.refineCreators(m.Creators)
+	if len(m.Creators) > 0 {
+		m.Creator = m.Creators[0].Name
+	}
+
+	m.refineCreators(m.Contributors)
+	if len(m.Contributors) > 0 {
+		m.Contributor = m.Contributors[0].Name
+	}
+}
+
+// refineCreators trims the names of creators and applies their EPUB 3 role
+// and file-as refinements
+func (m *Metadata) refineCreators(creators []Creator) {
+	for i := range creators {
+		c := &creators[i]
+		c.Name = strings.TrimSpace(c.Name)
+		c.Refinements = m.refinementsOf(c.ID)
+
+		if c.Role == "" {
+			c.Role = m.refinement(c.ID, "role")
+		}
+		if c.FileAs == "" {
+			c.FileAs = m.refinement(c.ID, "file-as")
+		}
+	}
+}
+
+// normalizeIdentifiers trims identifier values, applies EPUB 3
+// identifier-type refinements and sets the single Identifier field to the
+// identifier referenced by uniqueID, or the first identifier if none matches
+func (m *Metadata) normalizeIdentifiers(uniqueID string) {
+	for i := range m.Identifiers {
+		id := &m.Identifiers[i]
+		id.Value = strings.TrimSpace(id.Value)
+		id.Refinements = m.refinementsOf(id.ID)
+
+		if id.Scheme == "" {
+			id.Scheme = m.refinement(id.ID, "identifier-type")
+		}
+		if id.Scheme == "" && strings.HasPrefix(strings.ToLower(id.Value), "urn:isbn:") {
+			id.Scheme = "ISBN"
+		}
+	}
+
+	for _, id := range m.Identifiers {
+		if uniqueID != "" && id.ID == uniqueID {
+			m.Identifier = id.Value
+			return
+		}
+	}
+
+	if len(m.Identifiers) > 0 {
+		m.Identifier = m.Identifiers[0].Value
+	}
+}
+
+// trimISBNPrefix removes a "urn:isbn:" prefix from an identifier value
+func trimISBNPrefix(value string) string {
+	if len(value) >= 9 && strings.EqualFold(value[:9], "urn:isbn:") {
+		return value[9:]
+	}
+	return value
+}
+
+// isISBN reports whether value has the shape of an ISBN-10 or ISBN-13,
+// ignoring hyphens and spaces
+func isISBN(value string) bool {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(value)
+
+	switch len(digits) {
+	case 10:
+		for i, r := range digits {
+			if r >= '0' && r <= '9' || i == 9 && (r == 'X' || r == 'x') {
+				continue
+			}
+			return false
+		}
+		return true
+	case 13:
+		if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return false
+		}
+		for _, r := range digits {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// dateLayouts lists the date formats tried, in order, when parsing dates
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006",
+}
+
+// normalizeDates trims date values and sets the single Date field to the
+// publication date
+func (m *Metadata) normalizeDates() {
+	for i := range m.Dates {
+		m.Dates[i].Value = strings.TrimSpace(m.Dates[i].Value)
+	}
+	m.Date = m.publicationDate()
+}
+
+// publicationDate returns the date marked with the publication event, or the
+// first date without an event, or the first date
+func (m *Metadata) publicationDate() string {
+	for _, date := range m.Dates {
+		if strings.EqualFold(date.Event, "publication") {
+			return date.Value
+		}
+	}
+	for _, date := range m.Dates {
+		if date.Event == "" {
+			return date.Value
+		}
+	}
+	if len(m.Dates) > 0 {
+		return m.Dates[0].Value
+	}
+	return ""
+}
+
+// parseDate parses a metadata date, trying each of dateLayouts in order
+func parseDate(value string) (time.Time, error) {
+	var firstErr error
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse date %q: %w", value, firstErr)
+}
+
+// GetPublicationDate returns the publication date of the book
+//
+// For EPUB 2 books with several dates, the date with opf:event="publication"
+// is used. Dates are parsed as RFC 3339 timestamps, "YYYY-MM-DD" dates or bare
+// years, in that order. An error is returned if the book has no date or the
+// date cannot be parsed.
+func (e *Epub) GetPublicationDate() (time.Time, error) {
+	if e.Metadata.Date == "" {
+		return time.Time{}, fmt.Errorf("no publication date found")
+	}
+	return parseDate(e.Metadata.Date)
+}
+
+// GetModifiedDate returns the last modification date of the book
+//
+// The EPUB 3 <meta property="dcterms:modified"> element is used if present,
+// followed by an EPUB 2 date with opf:event="modification". An error is
+// returned if the book has no modification date or it cannot be parsed.
+func (e *Epub) GetModifiedDate() (time.Time, error) {
+	for _, meta := range e.Metadata.Meta {
+		if meta.Property == "dcterms:modified" && meta.Refines == "" {
+			return parseDate(strings.TrimSpace(meta.Value))
+		}
+	}
+
+	for _, date := range e.Metadata.Dates {
+		if strings.EqualFold(date.Event, "modification") {
+			return parseDate(date.Value)
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("no modification date found")
+}
+
+// compactStrings trims every value and drops the empty ones
+func compactStrings(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// GetSeries returns the series the book belongs to and its position in it
+//
+// The EPUB 3 <meta property="belongs-to-collection"> element with a
+// collection-type of "series" is preferred, with its group-position
+// refinement as the index. Otherwise the Calibre convention of
+// <meta name="calibre:series"> and <meta name="calibre:series_index"> is
+// used. ok is false if the book declares no series; index is zero if the
+// series declares no position.
+//
+// Example:
+//
+//	if name, index, ok := e.GetSeries(); ok {
+//		fmt.Printf("%s #%g\n", name, index)
+//	}
+func (e *Epub) GetSeries() (name string, index float64, ok bool) {
+	m := &e.Metadata
+
+	for _, meta := range m.Meta {
+		if meta.Property != "belongs-to-collection" || meta.Refines != "" {
+			continue
+		}
+		if m.refinement(meta.ID, "collection-type") != "series" {
+			continue
+		}
+
+		index, _ = strconv.ParseFloat(m.refinement(meta.ID, "group-position"), 64)
+		return strings.TrimSpace(meta.Value), index, true
+	}
+
+	for _, meta := range m.Meta {
+		if meta.Name == "calibre:series" && strings.TrimSpace(meta.Content) != "" {
+			name, ok = strings.TrimSpace(meta.Content), true
+		}
+		if meta.Name == "calibre:series_index" {
+			index, _ = strconv.ParseFloat(strings.TrimSpace(meta.Content), 64)
+		}
+	}
+	if !ok {
+		return "", 0, false
+	}
+
+	return name, index, true
+}
+
+// Collection represents a collection the book belongs to, such as a series or
+// a set of volumes
+//
+// Type is the collection-type refinement, either "series" for a sequence of
+// related works or "set" for a collection such as a box set, and is empty if
+// the book does not declare one. Position is the book's group-position within
+// the collection, or zero if it is not declared.
+type Collection struct {
+	Name     string
+	Type     string
+	Position float64
+}
+
+// GetCollections returns the collections the book belongs to
+//
+// Collections are read from EPUB 3 <meta property="belongs-to-collection">
+// elements and their collection-type and group-position refinements, in
+// document order. Collections nested inside another collection are not
+// included. Unlike GetSeries, the Calibre series metadata is not consulted.
+//
+// Example:
+//
+//	for _, c := range e.GetCollections() {
+//		fmt.Printf("%s (%s) #%g\n", c.Name, c.Type, c.Position)
+//	}
+func (e *Epub) GetCollections() []Collection {
+	m := &e.Metadata
+
+	var collections []Collection
+	for _, meta := range m.Meta {
+		if meta.Property != "belongs-to-collection" || meta.Refines != "" {
+			continue
+		}
+
+		position, _ := strconv.ParseFloat(m.refinement(meta.ID, "group-position"), 64)
+		collections = append(collections, Collection{
+			Name:     strings.TrimSpace(meta.Value),
+			Type:     m.refinement(meta.ID, "collection-type"),
+			Position: position,
+		})
+	}
+	return collections
+}
+
+// AccessibilityInfo holds the schema.org accessibility metadata of a book
+//
+// AccessModes lists the sensory modes needed to consume the content, such as
+// "textual" or "visual". Features lists the accessibility features it
+// provides, such as "alternativeText" or "tableOfContents", and Hazards its
+// physiological hazards, such as "flashing", or "none". Summary is the
+// human-readable accessibility summary.
+type AccessibilityInfo struct {
+	AccessModes []string
+	Features    []string
+	Hazards     []string
+	Summary     string
+}
+
+// GetAccessibility returns the accessibility metadata of the book
+//
+// The values are read from EPUB 3 <meta property="schema:accessMode">,
+// "schema:accessibilityFeature", "schema:accessibilityHazard" and
+// "schema:accessibilitySummary" elements, as required by EPUB Accessibility
+// 1.1, and from the equivalent <meta name="..." content="..."> elements used
+// by EPUB 2 books. Repeated properties are returned in document order. All
+// fields are empty if the book declares no accessibility metadata.
+//
+// Example:
+//
+//	info := e.GetAccessibility()
+//	if slices.Contains(info.Features, "alternativeText") {
+//		fmt.Println("images have text alternatives")
+//	}
+func (e *Epub) GetAccessibility() AccessibilityInfo {
+	var info AccessibilityInfo
+
+	for _, meta := range e.Metadata.Meta {
+		if meta.Refines != "" {
+			continue
+		}
+
+		property, value := meta.Property, meta.Value
+		if property == "" {
+			property, value = meta.Name, meta.Content
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch property {
+		case "schema:accessMode":
+			info.AccessModes = append(info.AccessModes, value)
+		case "schema:accessibilityFeature":
+			info.Features = append(info.Features, value)
+		case "schema:accessibilityHazard":
+			info.Hazards = append(info.Hazards, value)
+		case "schema:accessibilitySummary":
+			if info.Summary == "" {
+				info.Summary = value
+			}
+		}
+	}
+
+	return info
+}
+
+// Namespaces of the elements and attributes written by MarshalOPF
+const (
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+	opfNamespace = "http://www.idpf.org/2007/opf"
+)
+
+// opfMetadata is the layout of the <metadata> element written by
+// MarshalOPF
+//
+// The names carry their namespace prefix literally, since encoding/xml
+// cannot be told which prefix to use for a namespace.
+type opfMetadata struct {
+	XMLName      xml.Name        `xml:"metadata"`
+	DC           string          `xml:"xmlns:dc,attr"`
+	OPF          string          `xml:"xmlns:opf,attr"`
+	Titles       []opfText       `xml:"dc:title"`
+	Creators     []opfCreator    `xml:"dc:creator"`
+	Subjects     []string        `xml:"dc:subject"`
+	Description  string          `xml:"dc:description,omitempty"`
+	Publishers   []string        `xml:"dc:publisher"`
+	Contributors []opfCreator    `xml:"dc:contributor"`
+	Dates        []opfDate       `xml:"dc:date"`
+	Type         string          `xml:"dc:type,omitempty"`
+	Format       string          `xml:"dc:format,omitempty"`
+	Identifiers  []opfIdentifier `xml:"dc:identifier"`
+	Languages    []string        `xml:"dc:language"`
+	Rights       string          `xml:"dc:rights,omitempty"`
+	Sources      []string        `xml:"dc:source"`
+	Relations    []string        `xml:"dc:relation"`
+	Meta         []opfMeta       `xml:"meta"`
+}
+
+type opfText struct {
+	ID    string `xml:"id,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type opfCreator struct {
+	ID     string `xml:"id,attr,omitempty"`
+	Role   string `xml:"opf:role,attr,omitempty"`
+	FileAs string `xml:"opf:file-as,attr,omitempty"`
+	Name   string `xml:",chardata"`
+}
+
+type opfDate struct {
+	Event string `xml:"opf:event,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type opfIdentifier struct {
+	ID     string `xml:"id,attr,omitempty"`
+	Scheme string `xml:"opf:scheme,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+type opfMeta struct {
+	ID       string `xml:"id,attr,omitempty"`
+	Name     string `xml:"name,attr,omitempty"`
+	Content  string `xml:"content,attr,omitempty"`
+	Property string `xml:"property,attr,omitempty"`
+	Refines  string `xml:"refines,attr,omitempty"`
+	Scheme   string `xml:"scheme,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+// MarshalOPF serializes the metadata to a package document <metadata>
+// element
+//
+// Dublin Core elements are written with the dc: prefix, and every title,
+// creator, subject, publisher, contributor, date, identifier, language,
+// source, relation and meta element is repeated. The single value fields
+// such as Title and Language are only used when their repeatable
+// counterparts are empty. Creator and contributor roles and file-as names
+// and identifier schemes are written as EPUB 2 opf: attributes, unless they
+// came from an EPUB 3 refinement that is already among the meta elements.
+// The result can be embedded in a new package document; the book itself is
+// never modified.
+//
+// Example:
+//
+//	m := e.Metadata
+//	m.Titles = []epub.Title{{Value: "New Title"}}
+//	data, err := m.MarshalOPF()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (m Metadata) MarshalOPF() ([]byte, error) {
+	out := opfMetadata{
+		DC:          dcNamespace,
+		OPF:         opfNamespace,
+		Subjects:    m.Subjects,
+		Description: m.Description,
+		Publishers:  m.Publishers,
+		Type:        m.Type,
+		Format:      m.Format,
+		Languages:   m.Languages,
+		Rights:      m.Rights,
+		Sources:     m.Sources,
+		Relations:   m.Relations,
+	}
+
+	for _, title := range m.Titles {
+		out.Titles = append(out.Titles, opfText{ID: title.ID, Value: title.Value})
+	}
+	if len(out.Titles) == 0 && m.Title != "" {
+		out.Titles = []opfText{{Value: m.Title}}
+	}
+
+	out.Creators = m.opfCreators(m.Creators)
+	if len(out.Creators) == 0 && m.Creator != "" {
+		out.Creators = []opfCreator{{Name: m.Creator}}
+	}
+
+	if len(out.Publishers) == 0 && m.Publisher != "" {
+		out.Publishers = []string{m.Publisher}
+	}
+
+	out.Contributors = m.opfCreators(m.Contributors)
+	if len(out.Contributors) == 0 && m.Contributor != "" {
+		out.Contributors = []opfCreator{{Name: m.Contributor}}
+	}
+
+	if len(out.Subjects) == 0 && m.Subject != "" {
+		out.Subjects = []string{m.Subject}
+	}
+
+	for _, date := range m.Dates {
+		out.Dates = append(out.Dates, opfDate{Event: date.Event, Value: date.Value})
+	}
+	if len(out.Dates) == 0 && m.Date != "" {
+		out.Dates = []opfDate{{Value: m.Date}}
+	}
+
+	for _, id := range m.Identifiers {
+		identifier := opfIdentifier{ID: id.ID, Value: id.Value}
+		// Schemes derived from a refinement or a urn:isbn: value need no
+		// attribute to be read back
+		inferred := id.Scheme == "ISBN" && strings.HasPrefix(strings.ToLower(id.Value), "urn:isbn:")
+		if m.refinement(id.ID, "identifier-type") == "" && !inferred {
+			identifier.Scheme = id.Scheme
+		}
+		out.Identifiers = append(out.Identifiers, identifier)
+	}
+	if len(out.Identifiers) == 0 && m.Identifier != "" {
+		out.Identifiers = []opfIdentifier{{Value: m.Identifier}}
+	}
+
+	if len(out.Languages) == 0 && m.Language != "" {
+		out.Languages = []string{m.Language}
+	}
+
+	for _, meta := range m.Meta {
+		out.Meta = append(out.Meta, opfMeta(meta))
+	}
+
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return data, nil
+}
+
+// opfCreators converts creators or contributors to their opfCreator
+// layout, leaving out the roles and file-as names that came from a
+// refinement
+func (m Metadata) opfCreators(creators []Creator) []opfCreator {
+	var out []opfCreator
+	for _, c := range creators {
+		creator := opfCreator{ID: c.ID, Name: c.Name}
+		if m.refinement(c.ID, "role") == "" {
+			creator.Role = c.Role
+		}
+		if m.refinement(c.ID, "file-as") == "" {
+			creator.FileAs = c.FileAs
+		}
+		out = append(out, creator)
+	}
+	return out
+}
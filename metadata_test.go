@@ -0,0 +1,506 @@
+package epub
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testOPFWithMetadata returns testNavOPF with its metadata block replaced
+func testOPFWithMetadata(metadata string) string {
+	start := strings.Index(testNavOPF, "<metadata")
+	end := strings.Index(testNavOPF, "</metadata>") + len("</metadata>")
+	return testNavOPF[:start] + metadata + testNavOPF[end:]
+}
+
+// newMetadataTestEpub parses a test EPUB 3 book with the given metadata block
+func newMetadataTestEpub(t *testing.T, metadata string) *Epub {
+	t.Helper()
+
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = testOPFWithMetadata(metadata)
+	return newTestEpub(t, files)
+}
+
+func TestEpub_GetCreators(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	creators := epub.GetCreators()
+	if len(creators) != 1 {
+		t.Fatalf("Expected 1 creator, got %d", len(creators))
+	}
+
+	if creators[0].Role != "aut" {
+		t.Errorf("Expected role aut, got %q", creators[0].Role)
+	}
+
+	if epub.GetAuthor() != creators[0].Name {
+		t.Errorf("Expected GetAuthor to return first creator %q, got %q", creators[0].Name, epub.GetAuthor())
+	}
+}
+
+func TestEpub_GetCreators_Refinements(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<dc:creator id="author">Jane Doe</dc:creator>
+		<dc:creator id="translator">John Roe</dc:creator>
+		<meta refines="#author" property="role" scheme="marc:relators">aut</meta>
+		<meta refines="#author" property="file-as">Doe, Jane</meta>
+		<meta refines="#translator" property="role" scheme="marc:relators">trl</meta>
+	</metadata>`)
+
+	creators := epub.GetCreators()
+	if len(creators) != 2 {
+		t.Fatalf("Expected 2 creators, got %d", len(creators))
+	}
+
+	if creators[0].Name != "Jane Doe" || creators[0].Role != "aut" || creators[0].FileAs != "Doe, Jane" {
+		t.Errorf("Unexpected first creator: %+v", creators[0])
+	}
+
+	if creators[1].Name != "John Roe" || creators[1].Role != "trl" {
+		t.Errorf("Unexpected second creator: %+v", creators[1])
+	}
+
+	if epub.GetAuthor() != "Jane Doe" {
+		t.Errorf("Expected author Jane Doe, got %q", epub.GetAuthor())
+	}
+}
+
+func TestEpub_GetPublishersAndContributors(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+		<dc:title>Nav Test</dc:title>
+		<dc:publisher> University Press </dc:publisher>
+		<dc:publisher>Society of Letters</dc:publisher>
+		<dc:contributor id="editor">Ann Editor</dc:contributor>
+		<dc:contributor opf:role="ill" opf:file-as="Artist, Bob">Bob Artist</dc:contributor>
+		<meta refines="#editor" property="role" scheme="marc:relators">edt</meta>
+	</metadata>`)
+
+	publishers := epub.GetPublishers()
+	if want := []string{"University Press", "Society of Letters"}; !reflect.DeepEqual(publishers, want) {
+		t.Errorf("Unexpected publishers: got %q, want %q", publishers, want)
+	}
+	if epub.Metadata.Publisher != "University Press" {
+		t.Errorf("Expected Publisher to be the first publisher, got %q", epub.Metadata.Publisher)
+	}
+
+	contributors := epub.GetContributors()
+	if len(contributors) != 2 {
+		t.Fatalf("Expected 2 contributors, got %d", len(contributors))
+	}
+	if contributors[0].Name != "Ann Editor" || contributors[0].Role != "edt" {
+		t.Errorf("Unexpected first contributor: %+v", contributors[0])
+	}
+	if contributors[1].Name != "Bob Artist" || contributors[1].Role != "ill" || contributors[1].FileAs != "Artist, Bob" {
+		t.Errorf("Unexpected second contributor: %+v", contributors[1])
+	}
+	if epub.Metadata.Contributor != "Ann Editor" {
+		t.Errorf("Expected Contributor to be the first contributor, got %q", epub.Metadata.Contributor)
+	}
+}
+
+func TestEpub_GetSourcesAndRelations(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<dc:source>urn:isbn:9780000000002</dc:source>
+		<dc:source> Original manuscript </dc:source>
+		<dc:relation>Volume 2 of the collected works</dc:relation>
+		<dc:relation></dc:relation>
+	</metadata>`)
+
+	if want := []string{"urn:isbn:9780000000002", "Original manuscript"}; !reflect.DeepEqual(epub.GetSources(), want) {
+		t.Errorf("Unexpected sources: got %q, want %q", epub.GetSources(), want)
+	}
+	if want := []string{"Volume 2 of the collected works"}; !reflect.DeepEqual(epub.GetRelations(), want) {
+		t.Errorf("Unexpected relations: got %q, want %q", epub.GetRelations(), want)
+	}
+
+	if empty := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Nav Test</dc:title></metadata>`); empty.GetSources() != nil || empty.GetRelations() != nil {
+		t.Error("Expected no sources or relations")
+	}
+}
+
+func TestEpub_GetIdentifiers(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+		<dc:title>Nav Test</dc:title>
+		<dc:identifier opf:scheme="DOI">10.1000/182</dc:identifier>
+		<dc:identifier id="uid" opf:scheme="UUID">urn:uuid:1234</dc:identifier>
+		<dc:identifier id="isbn">978-3-16-148410-0</dc:identifier>
+	</metadata>`)
+
+	identifiers := epub.GetIdentifiers()
+	if len(identifiers) != 3 {
+		t.Fatalf("Expected 3 identifiers, got %d", len(identifiers))
+	}
+
+	if identifiers[0].Scheme != "DOI" || identifiers[0].Value != "10.1000/182" {
+		t.Errorf("Unexpected first identifier: %+v", identifiers[0])
+	}
+
+	if id := epub.GetIdentifier(); id != "urn:uuid:1234" {
+		t.Errorf("Expected unique identifier urn:uuid:1234, got %q", id)
+	}
+
+	if isbn := epub.GetISBN(); isbn != "978-3-16-148410-0" {
+		t.Errorf("Expected ISBN 978-3-16-148410-0, got %q", isbn)
+	}
+}
+
+func TestIsISBN(t *testing.T) {
+	tests := map[string]bool{
+		"0-306-40615-2":     true,
+		"080442957X":        true,
+		"9783161484100":     true,
+		"978 3 16 148410 0": true,
+		"1234567890123":     false,
+		"urn:uuid:1234":     false,
+		"12345":             false,
+	}
+
+	for value, want := range tests {
+		if got := isISBN(value); got != want {
+			t.Errorf("isISBN(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestEpub_GetPublicationDate(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	date, err := epub.GetPublicationDate()
+	if err != nil {
+		t.Fatalf("Failed to get publication date: %v", err)
+	}
+
+	if want := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, date)
+	}
+
+	if _, err := epub.GetModifiedDate(); err == nil {
+		t.Error("Expected error for missing modification date, got nil")
+	}
+}
+
+func TestEpub_GetDates_Events(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+		<dc:title>Nav Test</dc:title>
+		<dc:date opf:event="creation">2001-01-01</dc:date>
+		<dc:date opf:event="publication">1999</dc:date>
+		<dc:date opf:event="modification">2010-05-06T07:08:09Z</dc:date>
+	</metadata>`)
+
+	date, err := epub.GetPublicationDate()
+	if err != nil {
+		t.Fatalf("Failed to get publication date: %v", err)
+	}
+	if date.Year() != 1999 {
+		t.Errorf("Expected publication year 1999, got %v", date)
+	}
+
+	modified, err := epub.GetModifiedDate()
+	if err != nil {
+		t.Fatalf("Failed to get modification date: %v", err)
+	}
+	if want := time.Date(2010, 5, 6, 7, 8, 9, 0, time.UTC); !modified.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, modified)
+	}
+
+	epub = newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<dc:date>sometime in May</dc:date>
+		<meta property="dcterms:modified">2020-01-02T03:04:05Z</meta>
+	</metadata>`)
+
+	if _, err := epub.GetPublicationDate(); err == nil || !strings.Contains(err.Error(), "sometime in May") {
+		t.Errorf("Expected parse error mentioning the original date, got %v", err)
+	}
+
+	modified, err = epub.GetModifiedDate()
+	if err != nil {
+		t.Fatalf("Failed to get dcterms:modified date: %v", err)
+	}
+	if modified.Year() != 2020 {
+		t.Errorf("Expected modification year 2020, got %v", modified)
+	}
+}
+
+func TestEpub_GetSubjects(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<dc:subject> Fantasy </dc:subject>
+		<dc:subject></dc:subject>
+		<dc:subject>Adventure</dc:subject>
+	</metadata>`)
+
+	subjects := epub.GetSubjects()
+	if len(subjects) != 2 || subjects[0] != "Fantasy" || subjects[1] != "Adventure" {
+		t.Errorf("Unexpected subjects: %q", subjects)
+	}
+
+	if epub.Metadata.Subject != "Fantasy" {
+		t.Errorf("Expected Subject to hold the first subject, got %q", epub.Metadata.Subject)
+	}
+}
+
+func TestEpub_GetLanguages(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<dc:language>en</dc:language>
+		<dc:language> fr </dc:language>
+	</metadata>`)
+
+	languages := epub.GetLanguages()
+	if len(languages) != 2 || languages[0] != "en" || languages[1] != "fr" {
+		t.Errorf("Unexpected languages: %q", languages)
+	}
+
+	if epub.Metadata.Language != "en" {
+		t.Errorf("Expected Language to hold the primary language, got %q", epub.Metadata.Language)
+	}
+}
+
+func TestEpub_GetSeries(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+		<dc:title>Nav Test</dc:title>
+		<opf:meta name="calibre:series_index" content="2.5"/>
+		<opf:meta name="calibre:series" content="The Calibre Saga"/>
+	</metadata>`)
+
+	name, index, ok := epub.GetSeries()
+	if !ok || name != "The Calibre Saga" || index != 2.5 {
+		t.Errorf("Unexpected Calibre series: %q %v %v", name, index, ok)
+	}
+
+	epub = newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<meta property="belongs-to-collection" id="set">Box Set</meta>
+		<meta refines="#set" property="collection-type">set</meta>
+		<meta property="belongs-to-collection" id="series">The Saga</meta>
+		<meta refines="#series" property="collection-type">series</meta>
+		<meta refines="#series" property="group-position">3</meta>
+	</metadata>`)
+
+	name, index, ok = epub.GetSeries()
+	if !ok || name != "The Saga" || index != 3 {
+		t.Errorf("Unexpected EPUB 3 series: %q %v %v", name, index, ok)
+	}
+
+	if _, _, ok := newTestEpub(t, testEPUB3Files()).GetSeries(); ok {
+		t.Error("Expected no series for a book without series metadata")
+	}
+}
+
+func TestEpub_GetCollections(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<meta property="belongs-to-collection" id="set">Box Set</meta>
+		<meta refines="#set" property="collection-type">set</meta>
+		<meta refines="#set" property="group-position">2</meta>
+		<meta property="belongs-to-collection" id="series">The Saga</meta>
+		<meta refines="#series" property="collection-type">series</meta>
+		<meta refines="#series" property="group-position">3.5</meta>
+		<meta property="belongs-to-collection" refines="#series">Nested</meta>
+		<meta property="belongs-to-collection">Untyped</meta>
+		<meta name="calibre:series" content="The Calibre Saga"/>
+	</metadata>`)
+
+	want := []Collection{
+		{Name: "Box Set", Type: "set", Position: 2},
+		{Name: "The Saga", Type: "series", Position: 3.5},
+		{Name: "Untyped"},
+	}
+	if got := epub.GetCollections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected collections %+v, got %+v", want, got)
+	}
+
+	if got := newTestEpub(t, testEPUB3Files()).GetCollections(); got != nil {
+		t.Errorf("Expected no collections, got %+v", got)
+	}
+}
+
+func TestEpub_GetAccessibility(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>Nav Test</dc:title>
+		<meta property="schema:accessMode">textual</meta>
+		<meta property="schema:accessMode">visual</meta>
+		<meta property="schema:accessibilityFeature">alternativeText</meta>
+		<meta property="schema:accessibilityFeature">tableOfContents</meta>
+		<meta property="schema:accessibilityHazard">none</meta>
+		<meta property="schema:accessibilitySummary"> Images are described. </meta>
+		<meta name="schema:accessibilityFeature" content="structuralNavigation"/>
+	</metadata>`)
+
+	want := AccessibilityInfo{
+		AccessModes: []string{"textual", "visual"},
+		Features:    []string{"alternativeText", "tableOfContents", "structuralNavigation"},
+		Hazards:     []string{"none"},
+		Summary:     "Images are described.",
+	}
+	if info := epub.GetAccessibility(); !reflect.DeepEqual(info, want) {
+		t.Errorf("Unexpected accessibility info:\ngot  %+v\nwant %+v", info, want)
+	}
+
+	empty := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Nav Test</dc:title></metadata>`)
+	if info := empty.GetAccessibility(); !reflect.DeepEqual(info, AccessibilityInfo{}) {
+		t.Errorf("Expected no accessibility info, got %+v", info)
+	}
+}
+
+func TestMetadata_Refinements(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+		<dc:title id="t1"> Main Title </dc:title>
+		<dc:title id="t2">A Subtitle</dc:title>
+		<dc:title>Unrefined</dc:title>
+		<dc:creator id="c1">村上春樹</dc:creator>
+		<meta refines="#t1" property="title-type">main</meta>
+		<meta refines="#t2" property="title-type">subtitle</meta>
+		<meta refines="#t2" property="display-seq">2</meta>
+		<meta refines="#c1" property="alternate-script">Haruki Murakami</meta>
+		<meta refines="#c1" property="alternate-script">Murakami Haruki</meta>
+		<meta refines="#c1" property="file-as">Murakami, Haruki</meta>
+		<meta refines="#uid" property="identifier-type" scheme="onix:codelist5">15</meta>
+	</metadata>`)
+	m := epub.Metadata
+
+	if m.Title != "Main Title" || len(m.Titles) != 3 {
+		t.Fatalf("Unexpected titles: %q %+v", m.Title, m.Titles)
+	}
+	if got := m.Titles[1].Refinements; !reflect.DeepEqual(got, Refinements{"title-type": {"subtitle"}, "display-seq": {"2"}}) {
+		t.Errorf("Unexpected subtitle refinements: %v", got)
+	}
+	if m.Titles[2].Refinements != nil {
+		t.Errorf("Expected no refinements for an unrefined title, got %v", m.Titles[2].Refinements)
+	}
+
+	c := m.Creators[0]
+	if got := c.Refinements["alternate-script"]; !reflect.DeepEqual(got, []string{"Haruki Murakami", "Murakami Haruki"}) {
+		t.Errorf("Unexpected alternate scripts: %v", got)
+	}
+	if c.FileAs != "Murakami, Haruki" || c.Refinements.Get("file-as") != c.FileAs {
+		t.Errorf("Unexpected creator: %+v", c)
+	}
+
+	if got := m.Identifiers[0].Refinements.Get("identifier-type"); got != "15" {
+		t.Errorf("Expected identifier-type refinement, got %q", got)
+	}
+	if got := m.Identifiers[0].Refinements.Get("missing"); got != "" {
+		t.Errorf("Expected empty value for a missing refinement, got %q", got)
+	}
+}
+
+func TestEpub_GetTitles(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title id="t1">A Subtitle</dc:title>
+		<dc:title id="t2">The Collection</dc:title>
+		<dc:title id="t3">Main Title</dc:title>
+		<dc:title>Unordered</dc:title>
+		<meta refines="#t1" property="title-type">subtitle</meta>
+		<meta refines="#t1" property="display-seq">2</meta>
+		<meta refines="#t2" property="title-type">collection</meta>
+		<meta refines="#t3" property="title-type">main</meta>
+		<meta refines="#t3" property="display-seq">1</meta>
+	</metadata>`)
+
+	want := []TitledEntry{
+		{Value: "Main Title", Type: "main"},
+		{Value: "A Subtitle", Type: "subtitle"},
+		{Value: "The Collection", Type: "collection"},
+		{Value: "Unordered"},
+	}
+	if got := epub.GetTitles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected titles %+v, got %+v", want, got)
+	}
+	if got := epub.GetTitle(); got != "Main Title" {
+		t.Errorf("Expected main title, got %q", got)
+	}
+
+	epub = newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:title>First</dc:title>
+		<dc:title>Second</dc:title>
+	</metadata>`)
+	if got := epub.GetTitle(); got != "First" {
+		t.Errorf("Expected the first title without refinements, got %q", got)
+	}
+}
+
+func TestMetadata_MarshalOPF(t *testing.T) {
+	epub := newMetadataTestEpub(t, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+		<dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+		<dc:identifier id="isbn">urn:isbn:9780000000002</dc:identifier>
+		<dc:identifier opf:scheme="DOI">10.1000/182</dc:identifier>
+		<dc:title id="t1">Main &amp; Title</dc:title>
+		<dc:title id="t2">Subtitle</dc:title>
+		<meta refines="#t1" property="title-type">main</meta>
+		<dc:creator id="c1">Jane Doe</dc:creator>
+		<meta refines="#c1" property="role" scheme="marc:relators">aut</meta>
+		<dc:creator opf:role="trl" opf:file-as="Roe, Richard">Richard Roe</dc:creator>
+		<dc:subject>Fiction</dc:subject>
+		<dc:subject>Adventure</dc:subject>
+		<dc:date opf:event="publication">2020-01-02</dc:date>
+		<dc:publisher>Publisher</dc:publisher>
+		<dc:publisher>Second Publisher</dc:publisher>
+		<dc:contributor id="ed">Ed Itor</dc:contributor>
+		<meta refines="#ed" property="role" scheme="marc:relators">edt</meta>
+		<dc:contributor opf:role="ill">Ill Ustrator</dc:contributor>
+		<dc:rights>All rights reserved</dc:rights>
+		<dc:source>urn:isbn:9780000000019</dc:source>
+		<dc:relation>Sequel</dc:relation>
+		<dc:language>en</dc:language>
+		<dc:language>fr</dc:language>
+		<meta property="dcterms:modified">2021-03-04T05:06:07Z</meta>
+		<meta name="cover" content="cover-image"/>
+	</metadata>`)
+
+	data, err := epub.Metadata.MarshalOPF()
+	if err != nil {
+		t.Fatalf("Failed to marshal metadata: %v", err)
+	}
+
+	out := string(data)
+	for _, want := range []string{
+		`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">`,
+		`<dc:title id="t1">Main &amp; Title</dc:title>`,
+		`<dc:creator id="c1">Jane Doe</dc:creator>`,
+		`<dc:creator opf:role="trl" opf:file-as="Roe, Richard">Richard Roe</dc:creator>`,
+		`<dc:identifier id="isbn">urn:isbn:9780000000002</dc:identifier>`,
+		`<dc:identifier opf:scheme="DOI">10.1000/182</dc:identifier>`,
+		`<dc:date opf:event="publication">2020-01-02</dc:date>`,
+		`<dc:publisher>Second Publisher</dc:publisher>`,
+		`<dc:contributor id="ed">Ed Itor</dc:contributor>`,
+		`<dc:contributor opf:role="ill">Ill Ustrator</dc:contributor>`,
+		`<dc:source>urn:isbn:9780000000019</dc:source>`,
+		`<dc:relation>Sequel</dc:relation>`,
+		`<meta property="role" refines="#c1" scheme="marc:relators">aut</meta>`,
+		`<meta name="cover" content="cover-image"></meta>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %s\n%s", want, out)
+		}
+	}
+
+	reparsed := newMetadataTestEpub(t, out)
+	if !reflect.DeepEqual(reparsed.Metadata, epub.Metadata) {
+		t.Errorf("Expected metadata to survive a round trip\ngot  %+v\nwant %+v", reparsed.Metadata, epub.Metadata)
+	}
+
+	data, err = Metadata{Title: "Only Title", Creator: "Someone", Language: "de"}.MarshalOPF()
+	if err != nil {
+		t.Fatalf("Failed to marshal metadata: %v", err)
+	}
+	for _, want := range []string{"<dc:title>Only Title</dc:title>", "<dc:creator>Someone</dc:creator>", "<dc:language>de</dc:language>"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected output to contain %s\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MinifyStats describes the result of WriteMinified
+type MinifyStats struct {
+	// FilesKept is the number of files written, including the mimetype file
+	FilesKept int
+
+	// Dropped lists the files left out because nothing reaches them
+	Dropped []string
+
+	// BytesSaved is the total uncompressed size of the dropped files
+	BytesSaved int64
+}
+
+// cssRefPattern matches the url() and @import references of a stylesheet
+var cssRefPattern = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]*))\s*\)|@import\s+(?:"([^"]*)"|'([^']*)')`)
+
+// WriteMinified writes a copy of the EPUB without its unused files to w
+//
+// The files reachable from the spine, the navigation document, the NCX, the
+// guide and the cover image are kept, following every reference of the
+// content documents, stylesheets, SVG images and media overlays, including
+// hyperlinks, url() references and @import rules. All other files are
+// dropped, except the package document and the files in META-INF. The
+// manifest entries of dropped files are removed from the package document,
+// which is otherwise copied unchanged, as are all kept files.
+//
+// The new archive starts with an uncompressed mimetype entry, as required
+// by the OCF specification, and the other files are compressed with
+// Deflate. Only the default rendition of multiple-rendition EPUBs is
+// followed. Use WithMinifyReport to learn which files were dropped and how
+// many bytes were saved.
+//
+// Example:
+//
+//	f, err := os.Create("book.min.epub")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	err = e.WriteMinified(f, epub.WithMinifyReport(func(stats epub.MinifyStats) {
+//		fmt.Printf("dropped %d files, saved %d bytes\n", len(stats.Dropped), stats.BytesSaved)
+//	}))
+func (e *Epub) WriteMinified(w io.Writer, opts ...Option) error {
+	options := applyOptions(opts...)
+	if err := options.checkContext(); err != nil {
+		return err
+	}
+
+	reachable, err := e.reachableFiles()
+	if err != nil {
+		return err
+	}
+
+	stats := MinifyStats{FilesKept: 1}
+	zw := zip.NewWriter(w)
+
+	// The mimetype file must come first and be stored uncompressed
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	err = e.WalkFiles(func(name string, size int64, open func() (io.ReadCloser, error)) error {
+		if err := options.checkContext(); err != nil {
+			return err
+		}
+		if name == "mimetype" {
+			return nil
+		}
+		if !reachable[name] {
+			stats.Dropped = append(stats.Dropped, name)
+			if size > 0 {
+				stats.BytesSaved += size
+			}
+			return nil
+		}
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		stats.FilesKept++
+
+		if name == e.RootFile {
+			opf, err := e.getFile(name)
+			if err != nil {
+				return err
+			}
+			pruned, err := e.pruneManifest(opf, reachable)
+			if err != nil {
+				return fmt.Errorf("failed to rewrite %s: %w", name, err)
+			}
+			_, err = fw.Write(pruned)
+			return err
+		}
+
+		rc, err := open()
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", name, err)
+		}
+		defer rc.Close()
+		if _, err := io.Copy(fw, rc); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if options.MinifyReport != nil {
+		options.MinifyReport(stats)
+	}
+	return nil
+}
+
+// reachableFiles returns the set of files kept by WriteMinified
+//
+// Referenced files that do not exist are included under their resolved
+// path, so that their manifest entries are kept.
+func (e *Epub) reachableFiles() (map[string]bool, error) {
+	reachable := map[string]bool{e.RootFile: true}
+	for _, name := range e.src.list() {
+		if strings.HasPrefix(name, "META-INF/") {
+			reachable[name] = true
+		}
+	}
+	for _, rootfile := range e.rootfiles {
+		reachable[rootfile.FullPath] = true
+	}
+
+	// items maps the names of the manifest files to their items
+	items := make(map[string]*Item)
+	for i := range e.Manifest {
+		if name, ok := e.findFile(e.resolveHref(e.Manifest[i].Href)); ok {
+			items[name] = &e.Manifest[i]
+		}
+	}
+
+	var queue []string
+	add := func(p string) {
+		name, ok := e.findFile(p)
+		if !ok {
+			reachable[p] = true
+			return
+		}
+		if !reachable[name] {
+			reachable[name] = true
+			queue = append(queue, name)
+		}
+	}
+	addItem := func(item *Item) {
+		if item != nil {
+			add(e.resolveHref(item.Href))
+		}
+	}
+
+	for _, itemRef := range e.Spine {
+		addItem(e.findItemByID(itemRef.IDRef))
+	}
+	addItem(e.findNavItem())
+	addItem(e.findNCXItem())
+	addItem(e.findCoverItem())
+	for _, ref := range e.Guide {
+		if resolved := resolveRef(e.RootFile, strings.TrimSpace(ref.Href)); resolved != "" && !isExternalRef(ref.Href) {
+			add(resolved)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		var mediaType string
+		if item := items[name]; item != nil {
+			mediaType = itemMediaType(item)
+			if item.MediaOverlay != "" {
+				addItem(e.findItemByID(item.MediaOverlay))
+			}
+		}
+
+		var refs []string
+		switch {
+		case mediaType == "text/css" || mediaType == "" && strings.EqualFold(path.Ext(name), ".css"):
+			content, err := e.getFile(name)
+			if err != nil {
+				return nil, err
+			}
+			refs = cssRefs(string(content))
+		case strings.Contains(mediaType, "xml") || strings.Contains(mediaType, "html"):
+			content, err := e.getFile(name)
+			if err != nil {
+				return nil, err
+			}
+			refs, err = markupRefs(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+		}
+
+		for _, ref := range refs {
+			if ref = strings.TrimSpace(ref); ref == "" || isExternalRef(ref) {
+				continue
+			}
+			if resolved := resolveRef(name, ref); resolved != "" {
+				add(resolved)
+			}
+		}
+	}
+
+	return reachable, nil
+}
+
+// markupRefs returns the references of an (X)HTML, SVG, NCX or SMIL
+// document, including those of its inline stylesheets
+func markupRefs(content []byte) ([]string, error) {
+	var refs []string
+
+	d := newHTMLDecoder(content)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return refs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		for _, attr := range start.Attr {
+			switch name := strings.ToLower(attr.Name.Local); {
+			case name == "srcset":
+				for _, candidate := range strings.Split(attr.Value, ",") {
+					if fields := strings.Fields(candidate); len(fields) > 0 {
+						refs = append(refs, fields[0])
+					}
+				}
+			case name == "style":
+				refs = append(refs, cssRefs(attr.Value)...)
+			case resourceAttrs[name]:
+				refs = append(refs, attr.Value)
+			}
+		}
+
+		if strings.EqualFold(start.Name.Local, "style") {
+			css, err := collectRawText(d)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, cssRefs(css)...)
+		}
+	}
+}
+
+// cssRefs returns the url() and @import references of a stylesheet
+func cssRefs(css string) []string {
+	var refs []string
+	for _, match := range cssRefPattern.FindAllStringSubmatch(css, -1) {
+		for _, ref := range match[1:] {
+			if ref != "" {
+				refs = append(refs, ref)
+				break
+			}
+		}
+	}
+	return refs
+}
+
+// pruneManifest removes the manifest items whose files are not in keep from
+// a package document, leaving the rest of it unchanged
+func (e *Epub) pruneManifest(opf []byte, keep map[string]bool) ([]byte, error) {
+	var out []byte
+	d := xml.NewDecoder(bytes.NewReader(opf))
+
+	// copied is the offset up to which opf has been copied to out
+	copied := int64(0)
+	for {
+		offset := d.InputOffset()
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return append(out, opf[copied:]...), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "item" {
+			continue
+		}
+		href := getAttr(start, "href")
+		name, ok := e.findFile(e.resolveHref(href))
+		if !ok {
+			name = e.resolveHref(href)
+		}
+		if keep[name] {
+			continue
+		}
+
+		// Skip to the end of the item; the decoder reports one for
+		// self-closing elements too
+		for depth := 1; depth > 0; {
+			tok, err := d.RawToken()
+			if err != nil {
+				return nil, err
+			}
+			switch tok.(type) {
+			case xml.StartElement:
+				depth++
+			case xml.EndElement:
+				depth--
+			}
+		}
+		// Drop the indentation of the removed item along with it
+		out = bytes.TrimRight(append(out, opf[copied:offset]...), " \t\r\n")
+		copied = d.InputOffset()
+	}
+}
@@ -0,0 +1,94 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestEpub_WriteMinified(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `</manifest>`, `	<item id="css" href="css/style.css" media-type="text/css"/>
+		<item id="bg" href="images/bg.png" media-type="image/png"/>
+		<item id="font" href="fonts/serif.woff" media-type="font/woff"/>
+		<item id="unused" href="images/unused.png" media-type="image/png"/>
+	</manifest>`, 1)
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><link rel="stylesheet" href="../css/style.css"/></head>
+<body><p>One</p></body>
+</html>`
+	files["OEBPS/css/style.css"] = `@font-face { src: url("../fonts/serif.woff"); }
+body { background: url(../images/bg.png); }`
+	files["OEBPS/images/bg.png"] = "png"
+	files["OEBPS/fonts/serif.woff"] = "woff"
+	files["OEBPS/images/unused.png"] = "unused"
+	files["OEBPS/notes.txt"] = "leftover"
+	epub := newTestEpub(t, files)
+
+	var stats MinifyStats
+	var buf bytes.Buffer
+	err := epub.WriteMinified(&buf, WithMinifyReport(func(s MinifyStats) {
+		stats = s
+	}))
+	if err != nil {
+		t.Fatalf("Failed to write minified EPUB: %v", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read minified EPUB: %v", err)
+	}
+	if r.File[0].Name != "mimetype" || r.File[0].Method != zip.Store {
+		t.Errorf("Expected stored mimetype entry first, got %s (method %d)", r.File[0].Name, r.File[0].Method)
+	}
+
+	var names []string
+	for _, file := range r.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"META-INF/container.xml",
+		"OEBPS/content.opf",
+		"OEBPS/css/style.css",
+		"OEBPS/fonts/serif.woff",
+		"OEBPS/images/bg.png",
+		"OEBPS/nav.xhtml",
+		"OEBPS/text/one.xhtml",
+		"OEBPS/text/two.xhtml",
+		"OEBPS/toc.ncx",
+		"mimetype",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Unexpected files:\ngot  %v\nwant %v", names, want)
+	}
+
+	sort.Strings(stats.Dropped)
+	if want := []string{"OEBPS/images/unused.png", "OEBPS/notes.txt"}; !reflect.DeepEqual(stats.Dropped, want) {
+		t.Errorf("Unexpected dropped files: got %v, want %v", stats.Dropped, want)
+	}
+	if stats.BytesSaved != int64(len("unused")+len("leftover")) {
+		t.Errorf("Unexpected bytes saved: %d", stats.BytesSaved)
+	}
+	if stats.FilesKept != len(want) {
+		t.Errorf("Unexpected number of kept files: got %d, want %d", stats.FilesKept, len(want))
+	}
+
+	minified, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to open minified EPUB: %v", err)
+	}
+	if item := minified.findItemByID("unused"); item != nil {
+		t.Error("Expected manifest entry of the dropped image to be removed")
+	}
+	if item := minified.findItemByID("font"); item == nil {
+		t.Error("Expected manifest entry of the font to be kept")
+	}
+	if len(minified.Spine) != 2 {
+		t.Errorf("Expected 2 spine items, got %d", len(minified.Spine))
+	}
+}
@@ -0,0 +1,349 @@
+package epub
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// findNavItem finds the EPUB 3 navigation document in the manifest
+//
+// In EPUB 3 the navigation document is the manifest item whose properties
+// attribute contains "nav".
+func (e *Epub) findNavItem() *Item {
+	for i := range e.Manifest {
+		if e.Manifest[i].HasProperty("nav") {
+			return &e.Manifest[i]
+		}
+	}
+	return nil
+}
+
+// newHTMLDecoder returns an xml.Decoder configured to tolerate HTML
+//
+// XHTML content documents found in the wild are frequently not well-formed
+// XML, so the decoder runs in non-strict mode with the HTML entity table
+// and auto-closing of void elements such as <br> and <img>.
+func newHTMLDecoder(data []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	return d
+}
+
+// parseNavDocument parses the <nav> element of the given epub:type
+//
+// The ordered list inside the nav element is converted into a tree of
+// NavPoints: every <li> becomes a NavPoint whose label is the text of its
+// <a> (or <span> for unlinked headings) and whose Src is the link href.
+// A nested <ol> becomes the NavPoint's children. If no matching nav element
+// exists, nil is returned without an error.
+func parseNavDocument(data []byte, navType string) ([]NavPoint, error) {
+	d := newHTMLDecoder(data)
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || !strings.EqualFold(start.Name.Local, "nav") || !hasEpubType(start, navType) {
+			continue
+		}
+
+		return decodeNav(d)
+	}
+}
+
+// hasEpubType reports whether the element's epub:type attribute contains t
+func hasEpubType(start xml.StartElement, t string) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local != "type" || attr.Name.Space == "" {
+			continue
+		}
+		for _, v := range strings.Fields(attr.Value) {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getAttr returns the value of the named attribute, ignoring its namespace
+func getAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// decodeNav reads the content of a <nav> element up to its closing tag
+// and returns the entries of its top-level ordered list
+func decodeNav(d *xml.Decoder) ([]NavPoint, error) {
+	var points []NavPoint
+	depth := 1
+
+	for depth > 0 {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if strings.EqualFold(t.Name.Local, "ol") && points == nil {
+				points, err = decodeNavList(d)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return points, nil
+}
+
+// decodeNavList reads the <li> entries of an <ol> up to its closing tag
+func decodeNavList(d *xml.Decoder) ([]NavPoint, error) {
+	var points []NavPoint
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return points, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if strings.EqualFold(t.Name.Local, "li") {
+				point, err := decodeNavItem(d)
+				if err != nil {
+					return nil, err
+				}
+				points = append(points, point)
+				continue
+			}
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return points, nil
+		}
+	}
+}
+
+// decodeNavItem reads a single <li> entry up to its closing tag
+func decodeNavItem(d *xml.Decoder) (NavPoint, error) {
+	var point NavPoint
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return point, nil
+		}
+		if err != nil {
+			return point, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch strings.ToLower(t.Name.Local) {
+			case "a", "span":
+				if point.Label() == "" {
+					var label string
+					point.Src = getAttr(t, "href")
+					label, err = collectText(d)
+					point.Labels = []LocalizedText{{Lang: getAttr(t, "lang"), Text: label}}
+				} else {
+					err = d.Skip()
+				}
+			case "ol":
+				point.NavPoints, err = decodeNavList(d)
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return point, err
+			}
+		case xml.EndElement:
+			return point, nil
+		}
+	}
+}
+
+// collectText returns the whitespace-normalized text content of the current
+// element, consuming tokens up to and including its closing tag
+func collectText(d *xml.Decoder) (string, error) {
+	var sb strings.Builder
+	depth := 1
+
+	for depth > 0 {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			sb.Write(t)
+		}
+	}
+
+	return strings.Join(strings.Fields(sb.String()), " "), nil
+}
+
+// navPageTargets converts the entries of a page-list nav into PageTargets
+//
+// The page-list is a flat list, so nested entries are flattened in document
+// order. The label of each entry doubles as its page value.
+func navPageTargets(points []NavPoint) []PageTarget {
+	var pages []PageTarget
+	for _, point := range points {
+		if point.Src != "" {
+			pages = append(pages, PageTarget{
+				Value: point.Label(),
+				Label: point.Label(),
+				Src:   point.Src,
+			})
+		}
+		pages = append(pages, navPageTargets(point.NavPoints)...)
+	}
+	return pages
+}
+
+// Landmark represents an entry of the EPUB 3 landmarks navigation
+//
+// Type is the epub:type of the entry, such as "cover", "toc" or "bodymatter".
+// Href is relative to the directory of the package document, like the hrefs
+// of manifest items, and keeps the fragment of the link if any.
+type Landmark struct {
+	Type  string
+	Title string
+	Href  string
+}
+
+// parseLandmarks parses the landmarks nav of a navigation document
+//
+// Every link inside the nav element becomes a Landmark with its href left
+// unresolved. If there is no landmarks nav, nil is returned without an error.
+func parseLandmarks(data []byte) ([]Landmark, error) {
+	d := newHTMLDecoder(data)
+	var landmarks []Landmark
+	depth := 0
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return landmarks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if strings.EqualFold(t.Name.Local, "nav") && hasEpubType(t, "landmarks") {
+					depth = 1
+				}
+				continue
+			}
+			if !strings.EqualFold(t.Name.Local, "a") {
+				depth++
+				continue
+			}
+
+			landmark := Landmark{
+				Type: epubType(t),
+				Href: getAttr(t, "href"),
+			}
+			landmark.Title, err = collectText(d)
+			if err != nil {
+				return nil, err
+			}
+			landmarks = append(landmarks, landmark)
+		case xml.EndElement:
+			if depth > 0 {
+				depth--
+				if depth == 0 {
+					return landmarks, nil
+				}
+			}
+		}
+	}
+}
+
+// epubType returns the epub:type attribute of an element
+func epubType(start xml.StartElement) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "type" && attr.Name.Space != "" {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// navRelative converts a link found in the navigation document at navPath
+// into a path relative to the package document directory, keeping its
+// fragment
+func (e *Epub) navRelative(navPath, href string) string {
+	href = strings.TrimSpace(href)
+
+	var fragment string
+	if i := strings.Index(href, "#"); i >= 0 {
+		href, fragment = href[:i], href[i:]
+	}
+	if isExternalRef(href) || href+fragment == "" {
+		return href + fragment
+	}
+	if href == "" {
+		// A fragment-only link points into the navigation document itself
+		return e.packageRelative(navPath) + fragment
+	}
+
+	return e.packageRelative(resolveRef(navPath, href)) + fragment
+}
+
+// GetLandmarks returns the landmarks of the EPUB 3 navigation document
+//
+// Landmarks identify fundamental structural components of the book, such as
+// the cover, the table of contents and the start of the body matter. If the
+// book has no landmarks nav, nil is returned.
+//
+// Example:
+//
+//	for _, landmark := range e.GetLandmarks() {
+//		if landmark.Type == "bodymatter" {
+//			index, err := e.SpineIndexForHref(landmark.Href)
+//			// ...
+//		}
+//	}
+func (e *Epub) GetLandmarks() []Landmark {
+	return e.landmarks
+}
@@ -0,0 +1,211 @@
+package epub
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testNavOPF = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+	<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+		<dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+		<dc:title>Nav Test</dc:title>
+		<dc:language>en</dc:language>
+	</metadata>
+	<manifest>
+		<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+		<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+		<item id="c1" href="text/one.xhtml" media-type="application/xhtml+xml"/>
+		<item id="c2" href="text/two.xhtml" media-type="application/xhtml+xml"/>
+	</manifest>
+	<spine toc="ncx">
+		<itemref idref="c1"/>
+		<itemref idref="c2"/>
+	</spine>
+</package>`
+
+const testNavXHTML = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>Contents</title></head>
+<body>
+	<nav epub:type="toc" id="toc">
+		<h1>Contents</h1>
+		<ol>
+			<li><a href="text/one.xhtml">Part <em>One</em></a>
+				<ol>
+					<li><a href="text/one.xhtml#s1">Section 1.1</a></li>
+					<li><span>Unlinked</span>
+						<ol><li><a href="text/one.xhtml#s2">Deep</a></li></ol>
+					</li>
+				</ol>
+			</li>
+			<li><a href="text/two.xhtml">Part Two</a></li>
+		</ol>
+	</nav>
+	<nav epub:type="landmarks">
+		<ol><li><a epub:type="bodymatter" href="text/one.xhtml">Start</a></li></ol>
+	</nav>
+</body>
+</html>`
+
+const testNCX = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+	<docTitle><text>Nav Test</text></docTitle>
+	<navMap>
+		<navPoint id="n1" playOrder="1"><navLabel><text>NCX One</text></navLabel><content src="text/one.xhtml"/></navPoint>
+		<navPoint id="n2" playOrder="2"><navLabel><text>NCX Two</text></navLabel><content src="text/two.xhtml"/></navPoint>
+	</navMap>
+</ncx>`
+
+const testChapterXHTML = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>Chapter</title></head><body><p>Text</p></body></html>`
+
+// testEPUB3Files returns the files of a small EPUB 3 book with both a
+// navigation document and an NCX
+func testEPUB3Files() map[string]string {
+	return map[string]string{
+		"OEBPS/content.opf":    testNavOPF,
+		"OEBPS/nav.xhtml":      testNavXHTML,
+		"OEBPS/toc.ncx":        testNCX,
+		"OEBPS/text/one.xhtml": testChapterXHTML,
+		"OEBPS/text/two.xhtml": testChapterXHTML,
+	}
+}
+
+func TestParseNavTOC(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	if epub.TOC == nil {
+		t.Fatal("Expected TOC to be parsed from nav document")
+	}
+
+	nav := epub.TOC.NavMap
+	if len(nav) != 2 {
+		t.Fatalf("Expected 2 top-level entries, got %d", len(nav))
+	}
+
+	if nav[0].Label() != "Part One" || nav[0].Src != "text/one.xhtml" {
+		t.Errorf("Unexpected first entry: %q -> %q", nav[0].Label(), nav[0].Src)
+	}
+
+	if len(nav[0].NavPoints) != 2 {
+		t.Fatalf("Expected 2 children of first entry, got %d", len(nav[0].NavPoints))
+	}
+
+	if nav[0].NavPoints[0].Src != "text/one.xhtml#s1" {
+		t.Errorf("Expected child href text/one.xhtml#s1, got %q", nav[0].NavPoints[0].Src)
+	}
+
+	unlinked := nav[0].NavPoints[1]
+	if unlinked.Label() != "Unlinked" || unlinked.Src != "" {
+		t.Errorf("Unexpected unlinked entry: %q -> %q", unlinked.Label(), unlinked.Src)
+	}
+
+	if len(unlinked.NavPoints) != 1 || unlinked.NavPoints[0].Label() != "Deep" {
+		t.Errorf("Expected nested entry 'Deep' under unlinked heading")
+	}
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	if chapters[1].Title != "Part Two" {
+		t.Errorf("Expected second chapter title 'Part Two', got %q", chapters[1].Title)
+	}
+}
+
+func TestParseNavTOC_FallbackToNCX(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/nav.xhtml"] = `<html><body><nav epub:type="landmarks"><ol></ol></nav></body></html>`
+	epub := newTestEpub(t, files)
+
+	if epub.TOC == nil || len(epub.TOC.NavMap) != 2 {
+		t.Fatal("Expected TOC to fall back to the NCX file")
+	}
+
+	if epub.TOC.NavMap[0].Label() != "NCX One" {
+		t.Errorf("Expected NCX label, got %q", epub.TOC.NavMap[0].Label())
+	}
+}
+
+func TestParseTOC_BrokenNavWithoutNCX(t *testing.T) {
+	opf := strings.Replace(testNavOPF, "\t\t<item id=\"ncx\" href=\"toc.ncx\" media-type=\"application/x-dtbncx+xml\"/>\n", "", 1)
+	opf = strings.Replace(opf, ` toc="ncx"`, "", 1)
+
+	for name, nav := range map[string]string{
+		"missing":    "",
+		"unparsable": `<html><body><nav epub:type="toc"><ol><li><a href="text/one.xhtml">One</a></li></ol></nav></body></html`,
+	} {
+		files := testEPUB3Files()
+		files["OEBPS/content.opf"] = opf
+		delete(files, "OEBPS/toc.ncx")
+		if nav == "" {
+			delete(files, "OEBPS/nav.xhtml")
+		} else {
+			files["OEBPS/nav.xhtml"] = nav
+		}
+		data := buildTestZip(t, files)
+
+		epub, err := OpenBytes(data)
+		if err != nil {
+			t.Fatalf("%s nav: failed to open EPUB: %v", name, err)
+		}
+		if epub.TOC != nil || epub.GetTOCSource() != TOCSourceAuto {
+			t.Errorf("%s nav: expected no TOC, got %v from %v", name, epub.TOC, epub.GetTOCSource())
+		}
+
+		if _, err := OpenBytes(data, WithTOCSource(TOCSourceNav)); err == nil {
+			t.Errorf("%s nav: expected an error with TOCSourceNav", name)
+		}
+	}
+}
+
+func TestParseTOC_SpineTOCAttribute(t *testing.T) {
+	files := testEPUB3Files()
+	opf := strings.Replace(testNavOPF, `properties="nav"`, ``, 1)
+	opf = strings.Replace(opf, `media-type="application/x-dtbncx+xml"`, `media-type="text/xml"`, 1)
+	files["OEBPS/content.opf"] = opf
+	epub := newTestEpub(t, files)
+
+	if epub.TOC == nil || len(epub.TOC.NavMap) != 2 {
+		t.Fatal("Expected TOC to be parsed from the NCX named by the spine")
+	}
+
+	if epub.TOC.NavMap[0].Label() != "NCX One" {
+		t.Errorf("Expected NCX label, got %q", epub.TOC.NavMap[0].Label())
+	}
+}
+
+func TestEpub_GetLandmarks(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `href="nav.xhtml"`, `href="misc/nav.xhtml"`, 1)
+	delete(files, "OEBPS/nav.xhtml")
+	files["OEBPS/misc/nav.xhtml"] = `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+	<nav epub:type="toc"><ol><li><a href="../text/one.xhtml">One</a></li></ol></nav>
+	<nav epub:type="landmarks">
+		<h2>Guide</h2>
+		<ol>
+			<li><a epub:type="toc" href="#toc">Table of Contents</a></li>
+			<li><a epub:type="bodymatter" href="../text/two.xhtml#start">Start of <em>Content</em></a></li>
+		</ol>
+	</nav>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	want := []Landmark{
+		{Type: "toc", Title: "Table of Contents", Href: "misc/nav.xhtml#toc"},
+		{Type: "bodymatter", Title: "Start of Content", Href: "text/two.xhtml#start"},
+	}
+	if got := epub.GetLandmarks(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Unexpected landmarks:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	index, err := epub.SpineIndexForHref(want[1].Href)
+	if err != nil || index != 1 {
+		t.Errorf("Expected bodymatter landmark to map to spine index 1, got %d (%v)", index, err)
+	}
+}
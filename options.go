@@ -23,6 +23,36 @@ type epubOptions struct {
 	
 	// MaxContentLength limits the maximum size of content to process
 	MaxContentLength int64
+
+	// IncludeNonLinear includes spine items marked linear="no" in chapter lists
+	IncludeNonLinear bool
+
+	// CaseSensitive makes text searches case-sensitive
+	CaseSensitive bool
+
+	// CacheSize is the memory budget in bytes for caching decompressed files
+	CacheSize int64
+
+	// Progress is called after each chapter has been read
+	Progress func(done, total int)
+
+	// ForceUTF8 transcodes chapter content to UTF-8 from its declared charset
+	ForceUTF8 bool
+
+	// AllowedTags replaces the default element allowlist of GetChapterSanitized
+	AllowedTags []string
+
+	// TOCSource selects the document the table of contents is parsed from
+	TOCSource TOCSource
+
+	// SkipCoverPage excludes the cover page from chapter lists
+	SkipCoverPage bool
+
+	// SplitByHeading is the heading element chapters are split at
+	SplitByHeading string
+
+	// MinifyReport is called with the statistics of WriteMinified
+	MinifyReport func(stats MinifyStats)
 }
 
 // defaultOptions returns the default options
@@ -67,12 +97,137 @@ func WithChapterFilter(filter func(chapter Chapter) bool) Option {
 }
 
 // WithMaxContentLength sets the maximum content length to process
+//
+// Chapters whose content exceeds maxLen bytes are skipped or rejected by the
+// chapter methods. When passed to Open or one of the other constructors, the
+// limit also applies to every file read from the archive, including
+// container.xml, the package document and the table of contents, which are
+// rejected with ErrFileTooLarge once their decompressed size exceeds it.
 func WithMaxContentLength(maxLen int64) Option {
 	return func(opts *epubOptions) {
 		opts.MaxContentLength = maxLen
 	}
 }
 
+// WithIncludeNonLinear includes spine items marked linear="no" in chapter lists
+func WithIncludeNonLinear() Option {
+	return func(opts *epubOptions) {
+		opts.IncludeNonLinear = true
+	}
+}
+
+// WithCaseSensitive makes text searches case-sensitive
+func WithCaseSensitive() Option {
+	return func(opts *epubOptions) {
+		opts.CaseSensitive = true
+	}
+}
+
+// WithCacheSize enables caching of decompressed files when opening an EPUB
+//
+// Files read from the archive, such as the package document, the NCX, and
+// chapters or stylesheets that are accessed repeatedly, are kept in memory
+// and evicted least recently used first once the cache holds more than
+// maxBytes. The cache adds up to maxBytes to the memory retained by the Epub
+// for as long as it is in use. Caching is disabled by default and when
+// maxBytes is zero or negative. This option only has an effect when passed to
+// Open or one of the other constructors.
+func WithCacheSize(maxBytes int64) Option {
+	return func(opts *epubOptions) {
+		opts.CacheSize = maxBytes
+	}
+}
+
+// WithProgress reports progress while chapters are read
+//
+// GetChapters and ChaptersIter call fn after reading each HTML document of
+// the spine, with done being the number of documents read so far and total
+// the number that will be read. fn is called synchronously from the calling
+// goroutine and never after GetChapters returns or the iteration ends.
+func WithProgress(fn func(done, total int)) Option {
+	return func(opts *epubOptions) {
+		opts.Progress = fn
+	}
+}
+
+// WithForceUTF8 transcodes chapter content to UTF-8
+//
+// Some older EPUBs declare a legacy encoding such as GBK or Shift_JIS in the
+// XML declaration or a <meta charset> element of their content documents.
+// With this option, GetChapterContent, GetChapterContentBytes, GetChapters
+// and the methods built on them decode such chapters and rewrite the
+// declaration to UTF-8. Chapters declaring no encoding are assumed to be
+// UTF-8 already. GetChapterReader always returns the raw bytes.
+func WithForceUTF8() Option {
+	return func(opts *epubOptions) {
+		opts.ForceUTF8 = true
+	}
+}
+
+// WithAllowedTags sets the elements kept by GetChapterSanitized
+//
+// tags replaces the default allowlist and is matched case-insensitively
+// against element names without their namespace prefix. <script> elements
+// are removed even if listed.
+func WithAllowedTags(tags []string) Option {
+	return func(opts *epubOptions) {
+		opts.AllowedTags = tags
+	}
+}
+
+// WithTOCSource selects the document the table of contents is parsed from
+//
+// By default, TOCSourceAuto, the EPUB 3 navigation document is preferred and
+// the NCX is used as a fallback. TOCSourceNav and TOCSourceNCX use only the
+// given document, leaving the book without a table of contents if it is
+// missing. This option only has an effect when passed to Open or one of the
+// other constructors.
+func WithTOCSource(src TOCSource) Option {
+	return func(opts *epubOptions) {
+		opts.TOCSource = src
+	}
+}
+
+// WithSkipCoverPage excludes the cover page from chapter lists
+//
+// Many books start their spine with a page that only shows the cover image.
+// With this option, GetChapters, ChaptersIter and ReadingOrder skip that
+// page. To avoid dropping a real first chapter, a page is only treated as
+// the cover page if a guide reference or landmark of type "cover" points to
+// it and it is the first linear HTML document of the spine.
+func WithSkipCoverPage() Option {
+	return func(opts *epubOptions) {
+		opts.SkipCoverPage = true
+	}
+}
+
+// WithSplitByHeading splits documents into several chapters at headings
+//
+// Some books put many logical chapters into a single large document. With
+// this option, GetChapters and ChaptersIter split every document at each
+// heading element named tag, such as "h1" or "h2", and title each part with
+// the text of its heading. Each part is a well-formed document: the
+// elements enclosing its heading, such as <html> and <body>, are reopened
+// before and closed after its content, though only the first part keeps the
+// <head> of the document. Chapters are then numbered consecutively across
+// the whole book starting at 1, rather than by spine position, before
+// WithChapterFilter is applied.
+func WithSplitByHeading(tag string) Option {
+	return func(opts *epubOptions) {
+		opts.SplitByHeading = tag
+	}
+}
+
+// WithMinifyReport reports the result of WriteMinified
+//
+// fn is called once the minified EPUB has been written successfully, with
+// the files that were dropped and the number of bytes saved.
+func WithMinifyReport(fn func(stats MinifyStats)) Option {
+	return func(opts *epubOptions) {
+		opts.MinifyReport = fn
+	}
+}
+
 // applyOptions applies the given options to the default options
 func applyOptions(opts ...Option) *epubOptions {
 	options := defaultOptions()
@@ -0,0 +1,438 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// isExternalRef reports whether a reference points outside the EPUB, e.g. an
+// http(s) URL, a data URI or a mailto link
+func isExternalRef(ref string) bool {
+	if strings.HasPrefix(ref, "//") {
+		return true
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		// Unparseable references cannot be resolved inside the archive either
+		return strings.Contains(ref, ":")
+	}
+	return u.Scheme != ""
+}
+
+// resolveRef resolves a reference found in the document at docPath to a zip
+// path. The fragment and query of the reference are removed.
+func resolveRef(docPath, ref string) string {
+	if i := strings.IndexAny(ref, "#?"); i >= 0 {
+		ref = ref[:i]
+	}
+	if ref == "" {
+		return ""
+	}
+	return path.Join(path.Dir(filepath.ToSlash(docPath)), ref)
+}
+
+// GetChapterImages returns the paths of all images referenced by a chapter
+//
+// This method parses the chapter at the specified index and collects the
+// targets of <img src> and SVG <image xlink:href> references in document
+// order. Each reference is resolved relative to the chapter file, so the
+// returned paths are relative to the root of the EPUB and can be passed
+// directly to GetFileReader. External URLs and data URIs are skipped, and
+// each image is listed only once.
+//
+// Example:
+//
+//	images, err := e.GetChapterImages(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, image := range images {
+//		reader, err := e.GetFileReader(image)
+//		// ...
+//	}
+func (e *Epub) GetChapterImages(chapterIndex int) ([]string, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	chapterPath := e.resolveHref(item.Href)
+	content, err := e.getFile(chapterPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	var images []string
+	seen := make(map[string]bool)
+
+	d := newHTMLDecoder(content)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse chapter: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var ref string
+		switch strings.ToLower(start.Name.Local) {
+		case "img":
+			ref = getAttr(start, "src")
+		case "image":
+			ref = getAttr(start, "href")
+		default:
+			continue
+		}
+
+		ref = strings.TrimSpace(ref)
+		if ref == "" || isExternalRef(ref) {
+			continue
+		}
+
+		resolved := resolveRef(chapterPath, ref)
+		if resolved != "" && !seen[resolved] {
+			seen[resolved] = true
+			images = append(images, resolved)
+		}
+	}
+
+	return images, nil
+}
+
+// ResourceSet groups the non-document assets of an EPUB by kind
+type ResourceSet struct {
+	Images      []Item
+	Stylesheets []Item
+	Fonts       []Item
+	Audio       []Item
+	Video       []Item
+	Other       []Item
+}
+
+// extensionMediaTypes maps file extensions to the media type assumed for
+// manifest items that do not declare one
+var extensionMediaTypes = map[string]string{
+	".css":   "text/css",
+	".gif":   "image/gif",
+	".htm":   "text/html",
+	".html":  "text/html",
+	".jpeg":  "image/jpeg",
+	".jpg":   "image/jpeg",
+	".js":    "application/javascript",
+	".m4a":   "audio/mp4",
+	".mp3":   "audio/mpeg",
+	".mp4":   "video/mp4",
+	".ncx":   "application/x-dtbncx+xml",
+	".oga":   "audio/ogg",
+	".ogg":   "audio/ogg",
+	".otf":   "font/otf",
+	".png":   "image/png",
+	".smil":  "application/smil+xml",
+	".svg":   "image/svg+xml",
+	".ttf":   "font/ttf",
+	".webm":  "video/webm",
+	".webp":  "image/webp",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".xhtml": "application/xhtml+xml",
+}
+
+// itemMediaType returns the media type of an item, guessing it from the file
+// extension of its href if the manifest does not declare one
+func itemMediaType(item *Item) string {
+	if mediaType := strings.TrimSpace(item.MediaType); mediaType != "" {
+		return strings.ToLower(mediaType)
+	}
+	return extensionMediaTypes[strings.ToLower(path.Ext(item.Href))]
+}
+
+// isFontMediaType reports whether a media type denotes a font
+//
+// Besides the font/* types registered in RFC 8081, the legacy types used by
+// EPUB 2 and older tooling are recognized.
+func isFontMediaType(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "font/"),
+		strings.HasPrefix(mediaType, "application/font-"),
+		strings.HasPrefix(mediaType, "application/x-font-"),
+		mediaType == "application/vnd.ms-opentype",
+		mediaType == "application/vnd.ms-fontobject":
+		return true
+	}
+	return false
+}
+
+// Resources returns the non-document assets of the EPUB grouped by kind
+//
+// Every manifest item except the (X)HTML content documents and the NCX is
+// classified by its media type into images, stylesheets, fonts, audio, video
+// or other resources. Items that do not declare a media type are classified
+// by their file extension. Within each group, items keep their manifest
+// order.
+//
+// Example:
+//
+//	resources := e.Resources()
+//	for _, font := range resources.Fonts {
+//		fmt.Println(font.Href)
+//	}
+func (e *Epub) Resources() ResourceSet {
+	var set ResourceSet
+
+	for i := range e.Manifest {
+		item := e.Manifest[i]
+		mediaType := itemMediaType(&item)
+
+		switch {
+		case mediaType == "application/xhtml+xml",
+			mediaType == "text/html",
+			mediaType == "application/x-dtbncx+xml":
+			continue
+		case strings.HasPrefix(mediaType, "image/"):
+			set.Images = append(set.Images, item)
+		case mediaType == "text/css":
+			set.Stylesheets = append(set.Stylesheets, item)
+		case isFontMediaType(mediaType):
+			set.Fonts = append(set.Fonts, item)
+		case strings.HasPrefix(mediaType, "audio/"):
+			set.Audio = append(set.Audio, item)
+		case strings.HasPrefix(mediaType, "video/"):
+			set.Video = append(set.Video, item)
+		default:
+			set.Other = append(set.Other, item)
+		}
+	}
+
+	return set
+}
+
+// GetChapterStyles returns the CSS applied to a chapter
+//
+// This method parses the chapter at the specified index and collects the
+// stylesheets referenced by <link rel="stylesheet"> elements and the content
+// of inline <style> elements, in document order. Linked stylesheets are
+// resolved relative to the chapter file and read from the EPUB. External
+// stylesheets are skipped.
+//
+// Example:
+//
+//	styles, err := e.GetChapterStyles(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	css := strings.Join(styles, "\n")
+func (e *Epub) GetChapterStyles(chapterIndex int) ([]string, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	chapterPath := e.resolveHref(item.Href)
+	content, err := e.getFile(chapterPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	var styles []string
+
+	d := newHTMLDecoder(content)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse chapter: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch strings.ToLower(start.Name.Local) {
+		case "link":
+			if !isStylesheetLink(start) {
+				continue
+			}
+			ref := strings.TrimSpace(getAttr(start, "href"))
+			if ref == "" || isExternalRef(ref) {
+				continue
+			}
+
+			stylePath := resolveRef(chapterPath, ref)
+			data, err := e.getFile(stylePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read stylesheet: %w", err)
+			}
+			styles = append(styles, string(data))
+		case "style":
+			css, err := collectRawText(d)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse chapter: %w", err)
+			}
+			styles = append(styles, css)
+		}
+	}
+
+	return styles, nil
+}
+
+// isStylesheetLink reports whether a <link> element references a stylesheet
+func isStylesheetLink(start xml.StartElement) bool {
+	for _, rel := range strings.Fields(getAttr(start, "rel")) {
+		if strings.EqualFold(rel, "stylesheet") {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRawText returns the unmodified text content of the current element,
+// consuming tokens up to and including its closing tag
+func collectRawText(d *xml.Decoder) (string, error) {
+	var sb strings.Builder
+	depth := 1
+
+	for depth > 0 {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			sb.Write(t)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// linkAttrPattern matches the src and href attributes of a start tag
+var linkAttrPattern = regexp.MustCompile(`(?i)(\s(?:xlink:)?(?:src|href)\s*=\s*)("[^"]*"|'[^']*')`)
+
+// linkElements lists the elements whose references are rewritten by
+// GetChapterContentResolved
+var linkElements = map[string]bool{
+	"a":      true,
+	"image":  true,
+	"img":    true,
+	"link":   true,
+	"script": true,
+}
+
+// GetChapterContentResolved returns the content of a chapter with its
+// relative references rewritten to absolute zip paths
+//
+// The src and href attributes of <img>, <link>, <a>, <script> and SVG
+// <image> elements are resolved relative to the chapter file and replaced
+// with prefix followed by the resulting zip path, keeping any query or
+// fragment. Fragment-only references, external URLs and data URIs are left
+// untouched, as is the rest of the markup. This makes it possible to serve
+// the chapter and its resources through a custom URL scheme.
+//
+// Example:
+//
+//	content, err := e.GetChapterContentResolved(0, "epub://book/")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	// <img src="../images/a.png"> is now <img src="epub://book/OEBPS/images/a.png">
+func (e *Epub) GetChapterContentResolved(chapterIndex int, prefix string) (string, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return "", err
+	}
+
+	chapterPath := e.resolveHref(item.Href)
+	content, err := e.getFile(chapterPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	var sb strings.Builder
+	last := int64(0)
+
+	d := newHTMLDecoder(content)
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse chapter: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || !linkElements[strings.ToLower(start.Name.Local)] {
+			continue
+		}
+
+		end := d.InputOffset()
+		tag := string(content[offset:end])
+		rewritten := linkAttrPattern.ReplaceAllStringFunc(tag, func(attr string) string {
+			m := linkAttrPattern.FindStringSubmatch(attr)
+			quote := m[2][:1]
+			ref := html.UnescapeString(m[2][1 : len(m[2])-1])
+
+			resolved := resolveLink(chapterPath, ref, prefix)
+			if resolved == ref {
+				return attr
+			}
+			return m[1] + quote + html.EscapeString(resolved) + quote
+		})
+
+		sb.Write(content[last:offset])
+		sb.WriteString(rewritten)
+		last = end
+	}
+	sb.Write(content[last:])
+
+	return sb.String(), nil
+}
+
+// resolveLink rewrites a reference found in the document at docPath to
+// prefix followed by its zip path, keeping the query and fragment
+//
+// Empty and fragment-only references, external URLs and data URIs are
+// returned unchanged.
+func resolveLink(docPath, ref, prefix string) string {
+	trimmed := strings.TrimSpace(ref)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || isExternalRef(trimmed) {
+		return ref
+	}
+
+	var suffix string
+	if i := strings.IndexAny(trimmed, "#?"); i >= 0 {
+		suffix = trimmed[i:]
+	}
+
+	resolved := resolveRef(docPath, trimmed)
+	if resolved == "" {
+		return ref
+	}
+	return prefix + resolved + suffix
+}
@@ -0,0 +1,169 @@
+package epub
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEpub_GetChapterImages(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:xlink="http://www.w3.org/1999/xlink">
+<body>
+	<img src="../images/a.png" alt=""/>
+	<img src="https://example.com/remote.png"/>
+	<img src="data:image/png;base64,AAAA"/>
+	<svg><image xlink:href="../images/b.jpg"/></svg>
+	<img src="../images/a.png"/>
+	<img src="local.gif"/>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	images, err := epub.GetChapterImages(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter images: %v", err)
+	}
+
+	want := []string{"OEBPS/images/a.png", "OEBPS/images/b.jpg", "OEBPS/text/local.gif"}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("Unexpected images: got %v, want %v", images, want)
+	}
+
+	if _, err := epub.GetChapterImages(10); err == nil {
+		t.Error("Expected error for invalid chapter index, got nil")
+	}
+}
+
+func TestEpub_GetChapterImages_Cover(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	images, err := epub.GetChapterImages(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter images: %v", err)
+	}
+
+	if len(images) != 1 || images[0] != "OEBPS/cover.jpg" {
+		t.Fatalf("Expected cover image OEBPS/cover.jpg, got %v", images)
+	}
+
+	reader, err := epub.GetFileReader(images[0])
+	if err != nil {
+		t.Fatalf("Failed to open resolved image: %v", err)
+	}
+	reader.Close()
+}
+
+func TestEpub_Resources(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, `</manifest>`, `	<item id="css" href="style.css" media-type="text/css"/>
+		<item id="img" href="images/a.png" media-type="image/png"/>
+		<item id="img2" href="images/b.JPG"/>
+		<item id="font" href="fonts/a.otf" media-type="application/vnd.ms-opentype"/>
+		<item id="font2" href="fonts/b.woff2"/>
+		<item id="audio" href="audio/a.mp3" media-type="audio/mpeg"/>
+		<item id="video" href="video/a.mp4" media-type="video/mp4"/>
+		<item id="script" href="app.js" media-type="application/javascript"/>
+	</manifest>`, 1)
+	epub := newTestEpub(t, files)
+
+	ids := func(items []Item) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.ID)
+		}
+		return result
+	}
+
+	resources := epub.Resources()
+	tests := []struct {
+		name  string
+		items []Item
+		want  []string
+	}{
+		{"Images", resources.Images, []string{"img", "img2"}},
+		{"Stylesheets", resources.Stylesheets, []string{"css"}},
+		{"Fonts", resources.Fonts, []string{"font", "font2"}},
+		{"Audio", resources.Audio, []string{"audio"}},
+		{"Video", resources.Video, []string{"video"}},
+		{"Other", resources.Other, []string{"script"}},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.items); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEpub_GetChapterStyles(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+	<link rel="stylesheet" type="text/css" href="../styles/main.css"/>
+	<link rel="stylesheet" href="https://example.com/remote.css"/>
+	<link rel="icon" href="../icon.png"/>
+	<style>p > em { color: red; }</style>
+	<link rel="alternate stylesheet" href="local.css"/>
+</head>
+<body><p>Text</p></body>
+</html>`
+	files["OEBPS/styles/main.css"] = "body { margin: 0; }"
+	files["OEBPS/text/local.css"] = "h1 { font-size: 2em; }"
+	epub := newTestEpub(t, files)
+
+	styles, err := epub.GetChapterStyles(0)
+	if err != nil {
+		t.Fatalf("Failed to get chapter styles: %v", err)
+	}
+
+	want := []string{"body { margin: 0; }", "p > em { color: red; }", "h1 { font-size: 2em; }"}
+	if !reflect.DeepEqual(styles, want) {
+		t.Errorf("Unexpected styles: got %q, want %q", styles, want)
+	}
+
+	if _, err := epub.GetChapterStyles(10); err == nil {
+		t.Error("Expected error for invalid chapter index, got nil")
+	}
+}
+
+func TestEpub_GetChapterContentResolved(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html xmlns="http://www.w3.org/1999/xhtml">
+<head><link rel="stylesheet" href="../style.css"/><script src='app.js'></script></head>
+<body>
+	<p><img src="../images/a.png" alt="A &amp; B"/></p>
+	<p><a href="two.xhtml#s1">Next</a> <a href="#top">Top</a> <a href="https://example.com/">Web</a></p>
+	<p><img src="data:image/png;base64,AAAA"/></p>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	content, err := epub.GetChapterContentResolved(0, "epub://book/")
+	if err != nil {
+		t.Fatalf("Failed to get resolved chapter content: %v", err)
+	}
+
+	for _, want := range []string{
+		`href="epub://book/OEBPS/style.css"`,
+		`src='epub://book/OEBPS/text/app.js'`,
+		`<img src="epub://book/OEBPS/images/a.png" alt="A &amp; B"/>`,
+		`href="epub://book/OEBPS/text/two.xhtml#s1"`,
+		`href="#top"`,
+		`href="https://example.com/"`,
+		`src="data:image/png;base64,AAAA"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected resolved content to contain %s\n%s", want, content)
+		}
+	}
+
+	if _, err := epub.GetChapterContentResolved(10, ""); err == nil {
+		t.Error("Expected error for invalid chapter index, got nil")
+	}
+}
@@ -0,0 +1,311 @@
+package epub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// defaultAllowedTags lists the elements kept by GetChapterSanitized unless
+// WithAllowedTags is given
+var defaultAllowedTags = []string{
+	"html", "head", "title", "body",
+	"div", "span", "p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6",
+	"section", "article", "aside", "header", "footer", "nav", "main",
+	"a", "em", "strong", "b", "i", "u", "s", "sub", "sup", "small", "mark",
+	"abbr", "cite", "code", "pre", "kbd", "samp", "var", "q", "dfn", "time",
+	"del", "ins", "bdi", "bdo", "wbr", "ruby", "rt", "rp",
+	"blockquote", "ul", "ol", "li", "dl", "dt", "dd",
+	"table", "caption", "colgroup", "col", "thead", "tbody", "tfoot", "tr", "th", "td",
+	"img", "figure", "figcaption",
+}
+
+// sanitizeDropContent lists the elements that are removed together with
+// their content when they are not allowed, instead of being unwrapped
+var sanitizeDropContent = map[string]bool{
+	"script":   true,
+	"noscript": true,
+	"style":    true,
+	"template": true,
+	"iframe":   true,
+	"frame":    true,
+	"frameset": true,
+	"object":   true,
+	"embed":    true,
+	"applet":   true,
+}
+
+// GetChapterSanitized returns the content of a specific chapter with
+// scripts and other unsafe markup removed
+//
+// Elements not in the allowlist are unwrapped, keeping their text, except
+// for elements such as <style>, <iframe> and <object>, which are removed
+// together with their content. The default allowlist covers the document
+// structure, text formatting, lists, tables, links and images; use
+// WithAllowedTags to replace it, e.g. to keep <iframe> or <object>. <script>
+// elements, on* event handler attributes, javascript: URLs and references
+// to remote resources are always removed, even for allowed elements; style
+// attributes and the content of <style> elements are removed if they
+// reference a remote resource. Comments, CDATA sections and processing
+// instructions are removed as well. Plain links to external sites are kept.
+// The index is zero-based, as for GetChapterContent, and the other options
+// are applied as for it.
+//
+// Unlike GetChapterText, the result is still (X)HTML and keeps the safe
+// formatting of the chapter.
+//
+// Example:
+//
+//	content, err := e.GetChapterSanitized(0, epub.WithAllowedTags([]string{"p", "em", "iframe"}))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (e *Epub) GetChapterSanitized(chapterIndex int, opts ...Option) (string, error) {
+	content, err := e.GetChapterContent(chapterIndex, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	allowedTags := applyOptions(opts...).AllowedTags
+	if allowedTags == nil {
+		allowedTags = defaultAllowedTags
+	}
+	allowed := make(map[string]bool, len(allowedTags))
+	for _, tag := range allowedTags {
+		allowed[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	delete(allowed, "script")
+
+	sanitized, err := sanitizeHTML([]byte(content), allowed)
+	if err != nil {
+		return "", fmt.Errorf("failed to sanitize chapter: %w", err)
+	}
+	return sanitized, nil
+}
+
+// rawTextElements lists the elements whose content HTML parsers read as
+// text rather than markup
+var rawTextElements = map[string]bool{
+	"iframe":    true,
+	"noembed":   true,
+	"noframes":  true,
+	"noscript":  true,
+	"plaintext": true,
+	"script":    true,
+	"style":     true,
+	"textarea":  true,
+	"title":     true,
+	"xmp":       true,
+}
+
+var (
+	// xmlDeclPattern matches an XML declaration, the only markup other than
+	// elements and text that is kept
+	xmlDeclPattern = regexp.MustCompile(`^<\?xml\s[^<>]*\?>$`)
+	// attrNamePattern matches the attribute names written to the output
+	attrNamePattern = regexp.MustCompile(`^[a-z_][-a-z0-9_.:]*$`)
+	// cssStringPattern matches the quoted strings of a stylesheet, which
+	// @import and image-set() load without url(); unterminated strings
+	// extend to the end of the input, as in CSS
+	cssStringPattern = regexp.MustCompile(`"[^"]*(?:"|$)|'[^']*(?:'|$)`)
+	// cssURLPattern matches the targets of url() references, including
+	// unterminated ones
+	cssURLPattern = regexp.MustCompile(`(?i)url\(\s*([^)"'\s]*)`)
+	// cssScriptPattern matches the CSS extensions of older browsers that run
+	// script
+	cssScriptPattern = regexp.MustCompile(`(?i)expression\s*\(|-moz-binding|behavior\s*:`)
+)
+
+// textEscaper escapes text for both HTML and XHTML output
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// urlWhitespace removes the tabs and newlines browsers ignore inside URLs
+var urlWhitespace = strings.NewReplacer("\t", "", "\n", "", "\r", "")
+
+// sanitizeHTML removes the elements not in allowed and the unsafe
+// attributes of the remaining ones from an (X)HTML document
+//
+// The document is tokenized as HTML5, and the output is rebuilt from the
+// kept tags and the re-escaped text, so it reads the same whether it is
+// parsed as HTML or XHTML. Comments, CDATA sections, processing
+// instructions and doctypes are removed; only a leading XML declaration is
+// kept. The content of raw text elements such as <noscript> and <iframe>,
+// which XHTML parsers read as markup, is sanitized in turn, and that of a
+// kept <style> element is removed unless it is safe to copy as is.
+func sanitizeHTML(content []byte, allowed map[string]bool) (string, error) {
+	var sb strings.Builder
+	z := html.NewTokenizer(bytes.NewReader(content))
+
+	// dropping is the name of the element whose content is being removed,
+	// and depth the nesting of elements of that name inside it
+	dropping, depth := "", 0
+	// rawText is the name of the raw text element whose content is the next
+	// token, if any
+	rawText := ""
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return "", err
+			}
+			return sb.String(), nil
+		}
+
+		inRawText := rawText
+		rawText = ""
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tag, hasAttr := z.TagName()
+			name := string(tag)
+			selfClosing := tt == html.SelfClosingTagToken
+			if rawTextElements[name] {
+				if selfClosing {
+					// As in XHTML, a self-closing tag has no content
+					z.NextIsNotRawText()
+				} else {
+					rawText = name
+				}
+			}
+
+			if dropping != "" {
+				if name == dropping && !selfClosing {
+					depth++
+				}
+				continue
+			}
+			if !allowed[name] {
+				if sanitizeDropContent[name] && !selfClosing {
+					dropping, depth = name, 0
+				}
+				continue
+			}
+
+			sb.WriteString("<" + name)
+			for more := hasAttr; more; {
+				var key, value []byte
+				key, value, more = z.TagAttr()
+				if !attrNamePattern.Match(key) || isUnsafeAttr(name, string(key), string(value)) {
+					continue
+				}
+				sb.WriteString(" " + string(key) + `="` + html.EscapeString(string(value)) + `"`)
+			}
+			switch {
+			case !selfClosing:
+				sb.WriteString(">")
+			case rawTextElements[name]:
+				// HTML parsers would read the rest of the document as its
+				// content
+				sb.WriteString("></" + name + ">")
+			default:
+				sb.WriteString("/>")
+			}
+		case html.EndTagToken:
+			tag, _ := z.TagName()
+			name := string(tag)
+			if dropping != "" {
+				if name == dropping {
+					if depth == 0 {
+						dropping = ""
+					} else {
+						depth--
+					}
+				}
+				continue
+			}
+			if allowed[name] {
+				sb.WriteString("</" + name + ">")
+			}
+		case html.TextToken:
+			if dropping != "" {
+				continue
+			}
+			switch inRawText {
+			case "", "title", "textarea":
+				sb.WriteString(textEscaper.Replace(string(z.Text())))
+			case "style":
+				if css := string(z.Raw()); !strings.ContainsAny(css, "<&") && !isUnsafeCSS(css) {
+					sb.WriteString(css)
+				}
+			default:
+				inner, err := sanitizeHTML(z.Raw(), allowed)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(inner)
+			}
+		case html.CommentToken:
+			// The tokenizer reads processing instructions as comments
+			if dropping == "" && sb.Len() == 0 && xmlDeclPattern.Match(z.Raw()) {
+				sb.Write(z.Raw())
+			}
+		}
+	}
+}
+
+// isUnsafeAttr reports whether an attribute of the named element runs
+// script or loads a remote resource
+func isUnsafeAttr(element, key, value string) bool {
+	name := key
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		name = name[i+1:]
+	}
+	if strings.HasPrefix(name, "on") {
+		return true
+	}
+	if name == "style" {
+		return isUnsafeCSS(value)
+	}
+
+	value = strings.TrimSpace(value)
+	if isScriptURL(value) {
+		return true
+	}
+	if name != "srcset" {
+		value = urlWhitespace.Replace(value)
+	}
+	return resourceAttrs[name] && (name != "href" || resourceHrefElements[element]) && isRemoteRef(value)
+}
+
+// isUnsafeCSS reports whether a stylesheet or style attribute may load a
+// remote resource or run script
+//
+// Every url() target and quoted string is checked, and CSS escapes are
+// rejected outright, since they can spell either.
+func isUnsafeCSS(css string) bool {
+	if strings.Contains(css, `\`) || cssScriptPattern.MatchString(css) {
+		return true
+	}
+
+	var refs []string
+	for _, match := range cssURLPattern.FindAllStringSubmatch(css, -1) {
+		refs = append(refs, match[1])
+	}
+	for _, s := range cssStringPattern.FindAllString(css, -1) {
+		refs = append(refs, strings.Trim(s, `"'`))
+	}
+	for _, ref := range refs {
+		if isScriptURL(ref) || isRemoteRef(urlWhitespace.Replace(strings.TrimSpace(ref))) {
+			return true
+		}
+	}
+	return false
+}
+
+// isScriptURL reports whether a URL runs script when followed, ignoring the
+// whitespace and control characters browsers skip inside the scheme
+func isScriptURL(ref string) bool {
+	ref = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, ref)
+	return strings.HasPrefix(ref, "javascript:") || strings.HasPrefix(ref, "vbscript:")
+}
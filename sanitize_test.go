@@ -0,0 +1,102 @@
+package epub
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEpub_GetChapterSanitized(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>One</title><script>alert("<p>")</script><link rel="stylesheet" href="style.css"/></head>
+<body onload="init()">
+	<p epub:type="bridgehead" class="x">Some <em>safe</em> &amp; <b onclick="go()">bold</b> text<br/>here.</p>
+	<p><a href=" javascript:go()">Bad</a> <a href="https://example.com/?a=1&amp;b=2">Good</a></p>
+	<img src="https://example.com/track.png" alt="remote"/><img src="../images/a.png" alt="local"/>
+	<iframe src="frame.xhtml"><p>Fallback</p></iframe>
+	<font color="red">Unwrapped</font>
+</body>
+</html>`
+	epub := newTestEpub(t, files)
+
+	got, err := epub.GetChapterSanitized(0)
+	if err != nil {
+		t.Fatalf("Failed to sanitize chapter: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>One</title></head>
+<body>
+	<p epub:type="bridgehead" class="x">Some <em>safe</em> &amp; <b>bold</b> text<br/>here.</p>
+	<p><a>Bad</a> <a href="https://example.com/?a=1&amp;b=2">Good</a></p>
+	<img alt="remote"/><img src="../images/a.png" alt="local"/>
+	
+	Unwrapped
+</body>
+</html>`
+	if got != want {
+		t.Errorf("Unexpected sanitized content:\n%s\nwant:\n%s", got, want)
+	}
+
+	got, err = epub.GetChapterSanitized(0, WithAllowedTags([]string{"P", "iframe", "script"}))
+	if err != nil {
+		t.Fatalf("Failed to sanitize chapter: %v", err)
+	}
+	want = `<?xml version="1.0" encoding="UTF-8"?>
+
+One
+
+	<p epub:type="bridgehead" class="x">Some safe &amp; bold texthere.</p>
+	<p>Bad Good</p>
+	
+	<iframe src="frame.xhtml"><p>Fallback</p></iframe>
+	Unwrapped
+
+`
+	if got != want {
+		t.Errorf("Unexpected sanitized content with custom allowlist:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := epub.GetChapterSanitized(99); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	allowed := make(map[string]bool)
+	for _, tag := range append(defaultAllowedTags, "style", "noscript") {
+		allowed[tag] = true
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"cdata", `<p>a<![CDATA[><script>alert(1)</script>]]>b</p>`, `<p>a]]&gt;b</p>`},
+		{"comment", `<p>a<!--><script>alert(2)</script>-->b</p>`, `<p>a--&gt;b</p>`},
+		{"declarations", `<?php echo 1 ?><!DOCTYPE html><p>x</p>`, `<p>x</p>`},
+		{"remote style", `<p style="background:url(http://evil/x.png)">x</p>`, `<p>x</p>`},
+		{"escaped style", `<p style="background:u\72l(http://evil/x.png)">x</p>`, `<p>x</p>`},
+		{"local style", `<p style="color:red;background:url('../images/a.png')">x</p>`, `<p style="color:red;background:url(&#39;../images/a.png&#39;)">x</p>`},
+		{"unquoted attributes", `<img src=x onerror=alert(7)>`, `<img src="x">`},
+		{"remote url with tab", "<img src=\"ht\ttp://evil/x.png\"/>", `<img/>`},
+		{"self-closing script", `<head><script src="a.js"/></head><body><p>x</p></body>`, `<head></head><body><p>x</p></body>`},
+		{"remote style element", `<style>@import "http://evil/a.css"; p { color: red }</style>`, `<style></style>`},
+		{"style element markup", `<style>p{}<img src=x onerror=alert(8)></style>`, `<style></style>`},
+		{"local style element", `<style>p > em { color: red }</style>`, `<style>p > em { color: red }</style>`},
+		{"noscript", `<noscript><img src="https://evil/x.png" onerror="go()"/>&lt;</noscript>`, `<noscript><img/>&lt;</noscript>`},
+		{"rcdata", `<title>&lt;script&gt;</title>`, `<title>&lt;script&gt;</title>`},
+	}
+	for _, tt := range tests {
+		got, err := sanitizeHTML([]byte(tt.content), allowed)
+		if err != nil {
+			t.Errorf("%s: failed to sanitize: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package epub
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// snippetContext is the number of characters of context shown on either side
+// of a search match
+const snippetContext = 40
+
+// SearchHit represents an occurrence of a search query in the book
+//
+// Snippet holds the text surrounding the match, with line breaks replaced by
+// spaces. MatchStart and MatchEnd are the byte offsets of the match within
+// Snippet, so that Snippet[MatchStart:MatchEnd] is the matched text.
+type SearchHit struct {
+	ChapterIndex int
+	Title        string
+	Snippet      string
+	MatchStart   int
+	MatchEnd     int
+}
+
+// Search finds all occurrences of a query in the text of the book
+//
+// The search runs over the plain text of each chapter as returned by
+// GetChapterText, so markup never produces or hides matches. Matching is
+// case-insensitive unless WithCaseSensitive is given. Chapters are searched in
+// spine order, skipping non-linear items unless WithIncludeNonLinear is given,
+// and the context set with WithContext is checked between chapters.
+//
+// Example:
+//
+//	hits, err := e.Search("white whale")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, hit := range hits {
+//		fmt.Printf("%s: ...%s...\n", hit.Title, hit.Snippet)
+//	}
+func (e *Epub) Search(query string, opts ...Option) ([]SearchHit, error) {
+	options := applyOptions(opts...)
+
+	if query == "" {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	needle := []rune(query)
+	if !options.CaseSensitive {
+		needle = foldRunes(needle)
+	}
+
+	var hits []SearchHit
+	titles := e.tocTitles()
+
+	for i, itemRef := range e.Spine {
+		if err := options.checkContext(); err != nil {
+			return nil, err
+		}
+
+		if !itemRef.IsLinear() && !options.IncludeNonLinear {
+			continue
+		}
+
+		item := e.findItemByID(itemRef.IDRef)
+		if item == nil || !strings.Contains(item.MediaType, "html") {
+			continue
+		}
+
+		content, err := e.GetChapterContent(i, opts...)
+		if err != nil {
+			continue
+		}
+
+		text, err := htmlToText([]byte(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract chapter text: %w", err)
+		}
+
+		haystack := []rune(text)
+		folded := haystack
+		if !options.CaseSensitive {
+			folded = foldRunes(haystack)
+		}
+
+		var title string
+		for _, start := range indexRunes(folded, needle) {
+			if title == "" {
+				title = e.chapterTitle(i, item, []byte(content), titles)
+			}
+
+			hit := newSearchHit(haystack, start, start+len(needle))
+			hit.ChapterIndex = i
+			hit.Title = title
+			hits = append(hits, hit)
+		}
+	}
+
+	return hits, nil
+}
+
+// foldRunes returns a lower-cased copy of runes, preserving its length
+func foldRunes(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = unicode.ToLower(r)
+	}
+	return folded
+}
+
+// indexRunes returns the start positions of all non-overlapping occurrences
+// of needle in haystack
+func indexRunes(haystack, needle []rune) []int {
+	var positions []int
+
+	for i := 0; i+len(needle) <= len(haystack); {
+		if runesEqual(haystack[i:i+len(needle)], needle) {
+			positions = append(positions, i)
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+
+	return positions
+}
+
+// runesEqual reports whether two rune slices are equal
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newSearchHit builds a hit for the match text[start:end], given in runes
+func newSearchHit(text []rune, start, end int) SearchHit {
+	from := max(0, start-snippetContext)
+	to := min(len(text), end+snippetContext)
+
+	before := string(text[from:start])
+	match := string(text[start:end])
+	after := string(text[end:to])
+
+	return SearchHit{
+		Snippet:    strings.ReplaceAll(before+match+after, "\n", " "),
+		MatchStart: len(before),
+		MatchEnd:   len(before) + len(match),
+	}
+}
@@ -0,0 +1,77 @@
+package epub
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEpub_Search(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<html><body><p>Call me <b>Ishmael</b>. Some years ago, never mind how long.</p></body></html>`
+	files["OEBPS/text/two.xhtml"] = `<html><body><p>ISHMAEL again, and ishmael once more.</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	hits, err := epub.Search("ishmael")
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	if len(hits) != 3 {
+		t.Fatalf("Expected 3 case-insensitive hits, got %d", len(hits))
+	}
+
+	first := hits[0]
+	if first.ChapterIndex != 0 || first.Title != "Part One" {
+		t.Errorf("Unexpected first hit: %+v", first)
+	}
+
+	if got := first.Snippet[first.MatchStart:first.MatchEnd]; got != "Ishmael" {
+		t.Errorf("Expected highlighted match Ishmael, got %q", got)
+	}
+
+	hits, err = epub.Search("ISHMAEL", WithCaseSensitive())
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ChapterIndex != 1 {
+		t.Errorf("Expected a single case-sensitive hit in chapter 1, got %+v", hits)
+	}
+
+	hits, err = epub.Search("<b>")
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("Expected markup not to be searchable, got %d hits", len(hits))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := epub.Search("ishmael", WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEpub_Search_Unicode(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	hits, err := epub.Search("百世书")
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	if len(hits) == 0 {
+		t.Fatal("Expected hits for 百世书")
+	}
+
+	hit := hits[0]
+	if hit.Snippet[hit.MatchStart:hit.MatchEnd] != "百世书" {
+		t.Errorf("Unexpected match offsets in snippet %q", hit.Snippet)
+	}
+}
@@ -0,0 +1,151 @@
+package epub
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// source provides access to the files that make up an EPUB
+//
+// Names are slash-separated paths relative to the root of the EPUB, exactly
+// as listed by list. The parsing code only reads files through a source, so
+// that books can be read from zip archives as well as from unpacked
+// directory trees.
+type source interface {
+	// open opens the named file for reading
+	open(name string) (io.ReadCloser, error)
+
+	// list returns the names of all files
+	list() []string
+}
+
+// sizer is implemented by sources that know the uncompressed size of their
+// files without reading them
+type sizer interface {
+	size(name string) (int64, bool)
+}
+
+// archiveSource is implemented by sources backed by an archive, whose list
+// method returns the entries in archive order
+type archiveSource interface {
+	source
+
+	// method returns the compression method of the named entry
+	method(name string) (uint16, bool)
+}
+
+// zipSource reads the files of an EPUB from a zip archive
+type zipSource struct {
+	names []string
+	files map[string]*zip.File
+}
+
+// newZipSource returns a source reading from the zip archive r
+//
+// Entries with unsafe names, as reported by isUnsafePath, are left out.
+func newZipSource(r *zip.Reader) *zipSource {
+	s := &zipSource{
+		names: make([]string, 0, len(r.File)),
+		files: make(map[string]*zip.File, len(r.File)),
+	}
+	for _, file := range r.File {
+		// Entries that could escape the root of the EPUB when extracted
+		// are not part of it
+		if isUnsafePath(file.Name) {
+			continue
+		}
+		s.names = append(s.names, file.Name)
+		if _, ok := s.files[file.Name]; !ok {
+			s.files[file.Name] = file
+		}
+	}
+	return s
+}
+
+func (s *zipSource) open(name string) (io.ReadCloser, error) {
+	file, ok := s.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return file.Open()
+}
+
+func (s *zipSource) list() []string {
+	return s.names
+}
+
+func (s *zipSource) size(name string) (int64, bool) {
+	file, ok := s.files[name]
+	if !ok {
+		return 0, false
+	}
+	return int64(file.UncompressedSize64), true
+}
+
+func (s *zipSource) method(name string) (uint16, bool) {
+	file, ok := s.files[name]
+	if !ok {
+		return 0, false
+	}
+	return file.Method, true
+}
+
+// fsSource reads the files of an unpacked EPUB from a file system
+type fsSource struct {
+	fsys  fs.FS
+	names []string
+}
+
+// newFSSource returns a source reading from fsys, listing its regular files
+func newFSSource(fsys fs.FS) (*fsSource, error) {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &fsSource{fsys: fsys, names: names}, nil
+}
+
+func (s *fsSource) open(name string) (io.ReadCloser, error) {
+	return s.fsys.Open(name)
+}
+
+func (s *fsSource) list() []string {
+	return s.names
+}
+
+func (s *fsSource) size(name string) (int64, bool) {
+	info, err := fs.Stat(s.fsys, name)
+	if err != nil {
+		return 0, false
+	}
+	return info.Size(), true
+}
+
+// isUnsafePath reports whether a file name could refer to a location outside
+// the root of the EPUB, because it is absolute or contains ".." elements
+//
+// Backslashes are treated as separators, since some archivers write Windows
+// paths into zip entries.
+func isUnsafePath(name string) bool {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") || len(name) >= 2 && name[1] == ':' {
+		return true
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestOpenFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mimetype":               {Data: []byte("application/epub+zip")},
+		"META-INF/container.xml": {Data: []byte(testContainerXML)},
+	}
+	for name, content := range testEPUB3Files() {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	epub, err := OpenFS(fsys)
+	if err != nil {
+		t.Fatalf("Failed to open EPUB from fs.FS: %v", err)
+	}
+	defer epub.Close()
+
+	if epub.File != nil {
+		t.Error("Expected File to be nil for an fs.FS backed EPUB")
+	}
+	if epub.GetTitle() != "Nav Test" {
+		t.Errorf("Expected title 'Nav Test', got %q", epub.GetTitle())
+	}
+	if epub.TOC == nil || len(epub.TOC.NavMap) != 2 {
+		t.Error("Expected TOC to be parsed from the navigation document")
+	}
+
+	content, err := epub.GetChapterContent(0, WithMaxContentLength(1<<10))
+	if err != nil {
+		t.Fatalf("Failed to get chapter content: %v", err)
+	}
+	if content != testChapterXHTML {
+		t.Errorf("Unexpected chapter content: %q", content)
+	}
+
+	if problems := epub.Validate(); len(problems) != 0 {
+		t.Errorf("Expected no validation problems, got %v", problems)
+	}
+
+	delete(fsys, "OEBPS/content.opf")
+	if _, err := OpenFS(fsys); !errors.Is(err, ErrInvalidEPUB) {
+		t.Errorf("Expected ErrInvalidEPUB without package document, got %v", err)
+	}
+}
+
+func TestZipSource(t *testing.T) {
+	data := buildTestZip(t, testEPUB3Files())
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to read test zip: %v", err)
+	}
+	src := newZipSource(r)
+
+	names := src.list()
+	if len(names) != len(r.File) || names[0] != "mimetype" {
+		t.Errorf("Expected entries in archive order starting with mimetype, got %v", names)
+	}
+
+	rc, err := src.open("OEBPS/text/one.xhtml")
+	if err != nil {
+		t.Fatalf("Failed to open entry: %v", err)
+	}
+	content, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(content) != testChapterXHTML {
+		t.Errorf("Unexpected entry content: %q", content)
+	}
+
+	if size, ok := src.size("OEBPS/text/one.xhtml"); !ok || size != int64(len(testChapterXHTML)) {
+		t.Errorf("Expected size %d, got %d (%v)", len(testChapterXHTML), size, ok)
+	}
+	if method, ok := src.method("mimetype"); !ok || method != zip.Store {
+		t.Errorf("Expected stored mimetype entry, got method %d (%v)", method, ok)
+	}
+
+	if _, err := src.open("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for missing entry, got %v", err)
+	}
+}
+
+func TestZipSource_UnsafePaths(t *testing.T) {
+	files := testEPUB3Files()
+	files["../evil.xhtml"] = "evil"
+	files["OEBPS/../../evil.css"] = "evil"
+	data := buildTestZip(t, files)
+
+	epub, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+
+	for _, name := range epub.src.list() {
+		if strings.Contains(name, "evil") {
+			t.Errorf("Expected unsafe entry %s to be left out", name)
+		}
+	}
+	if _, err := epub.GetFileReader("OEBPS/../../evil.css"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound for an unsafe path, got %v", err)
+	}
+	if _, err := epub.GetFileReader("OEBPS/text/../nav.xhtml"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Expected paths with .. elements to be rejected, got %v", err)
+	}
+}
+
+func TestIsUnsafePath(t *testing.T) {
+	tests := map[string]bool{
+		"OEBPS/content.opf":   false,
+		"OEBPS/a..b.xhtml":    false,
+		"../evil":             true,
+		"OEBPS/../../evil":    true,
+		"/etc/passwd":         true,
+		`OEBPS\..\..\evil`:    true,
+		`C:\Windows\evil.dll`: true,
+		"OEBPS/text/..":       true,
+		"OEBPS/text/.hidden":  false,
+	}
+	for name, want := range tests {
+		if got := isUnsafePath(name); got != want {
+			t.Errorf("isUnsafePath(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package epub
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// splitChapter splits a chapter at every start tag of the heading element
+// named tag, such as "h1"
+//
+// Each section holds the content from its heading up to the next one. The
+// first section keeps the markup before its heading and the last the markup
+// after its end, while the elements enclosing a heading, such as <html>,
+// <body> or a <section>, are reopened before and closed after the slice of
+// every other section, so that each section is a well-formed document of
+// its own. Each section is titled with the text of its heading. Content
+// before the first heading becomes a section of its own, titled like the
+// whole chapter, only if it contains text. If the chapter has no such
+// heading or cannot be parsed, it is returned unsplit.
+func splitChapter(chapter Chapter, content []byte, tag string) []Chapter {
+	// openElement is an element enclosing the current position, with its
+	// start tag as written in the content
+	type openElement struct {
+		name     string
+		startTag string
+	}
+	type boundary struct {
+		offset int64
+		title  string
+		open   []openElement
+	}
+	var boundaries []boundary
+	var open []openElement
+	// preamble reports whether there is text in the body before the first
+	// heading
+	preamble, inBody := false, false
+
+	d := newHTMLDecoder(content)
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return []Chapter{chapter}
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch name := t.Name.Local; {
+			case strings.EqualFold(name, tag):
+				title, err := collectText(d)
+				if err != nil {
+					return []Chapter{chapter}
+				}
+				boundaries = append(boundaries, boundary{
+					offset: offset,
+					title:  title,
+					open:   append([]openElement(nil), open...),
+				})
+				continue
+			case strings.EqualFold(name, "body"):
+				inBody = true
+			}
+			open = append(open, openElement{name: t.Name.Local, startTag: string(content[offset:d.InputOffset()])})
+		case xml.EndElement:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case xml.CharData:
+			if inBody && len(boundaries) == 0 && strings.TrimSpace(string(t)) != "" {
+				preamble = true
+			}
+		}
+	}
+
+	if len(boundaries) == 0 {
+		return []Chapter{chapter}
+	}
+	if !preamble {
+		boundaries[0].offset, boundaries[0].open = 0, nil
+	} else {
+		boundaries = append([]boundary{{title: chapter.Title}}, boundaries...)
+	}
+
+	sections := make([]Chapter, 0, len(boundaries))
+	for i, b := range boundaries {
+		var sb strings.Builder
+		for _, el := range b.open {
+			sb.WriteString(el.startTag)
+		}
+
+		if i+1 < len(boundaries) {
+			next := boundaries[i+1]
+			sb.Write(content[b.offset:next.offset])
+			for j := len(next.open) - 1; j >= 0; j-- {
+				sb.WriteString("</" + next.open[j].name + ">")
+			}
+		} else {
+			sb.Write(content[b.offset:])
+		}
+
+		title := b.title
+		if title == "" {
+			title = chapter.Title
+		}
+		sections = append(sections, Chapter{
+			Title:   title,
+			Content: sb.String(),
+			Href:    chapter.Href,
+			ID:      chapter.ID,
+		})
+	}
+	return sections
+}
@@ -0,0 +1,442 @@
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// blockElements lists the HTML elements that start a new paragraph in
+// extracted text
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true,
+	"figure": true, "footer": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "tr": true, "ul": true,
+}
+
+// skippedElements lists the HTML elements whose content is dropped entirely
+// from extracted text
+var skippedElements = map[string]bool{
+	"head":     true,
+	"script":   true,
+	"style":    true,
+	"template": true,
+}
+
+// textBuilder accumulates extracted text, collapsing whitespace and
+// deferring line breaks until the next visible text
+type textBuilder struct {
+	sb           strings.Builder
+	pendingBreak int
+	pendingSpace bool
+}
+
+// writeText appends a run of character data with whitespace collapsed
+func (b *textBuilder) writeText(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" {
+			b.pendingSpace = true
+		}
+		return
+	}
+
+	if b.sb.Len() > 0 {
+		if b.pendingBreak > 0 {
+			b.sb.WriteString(strings.Repeat("\n", b.pendingBreak))
+		} else if b.pendingSpace || startsWithSpace(s) {
+			b.sb.WriteByte(' ')
+		}
+	}
+
+	b.sb.WriteString(strings.Join(words, " "))
+	b.pendingBreak = 0
+	b.pendingSpace = endsWithSpace(s)
+}
+
+// lineBreak requests n newlines before the next visible text
+func (b *textBuilder) lineBreak(n int) {
+	if n > b.pendingBreak {
+		b.pendingBreak = n
+	}
+	b.pendingSpace = false
+}
+
+// String returns the text accumulated so far
+func (b *textBuilder) String() string {
+	return b.sb.String()
+}
+
+// startsWithSpace reports whether s begins with whitespace
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// endsWithSpace reports whether s ends with whitespace
+func endsWithSpace(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// htmlToText converts an (X)HTML document to plain text
+//
+// Tags are removed and entities decoded. Whitespace is collapsed, block
+// elements are separated by blank lines and <br> produces a single line
+// break. The content of <head>, <script> and <style> elements is dropped.
+func htmlToText(content []byte) (string, error) {
+	d := newHTMLDecoder(content)
+	var b textBuilder
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case skippedElements[name]:
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			case name == "br":
+				b.lineBreak(1)
+			case blockElements[name]:
+				b.lineBreak(2)
+			}
+		case xml.EndElement:
+			if blockElements[strings.ToLower(t.Name.Local)] {
+				b.lineBreak(2)
+			}
+		case xml.CharData:
+			b.writeText(string(t))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// extractHTMLTitle returns the title of an (X)HTML document
+//
+// The text of the <title> element is used if it is non-empty; otherwise the
+// text of the first <h1> or <h2> heading is returned. Entities are decoded
+// and whitespace is trimmed and collapsed. If the document has neither, an
+// empty string is returned.
+func extractHTMLTitle(content []byte) string {
+	d := newHTMLDecoder(content)
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return ""
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch strings.ToLower(start.Name.Local) {
+		case "title":
+			title, err := collectText(d)
+			if err != nil {
+				return ""
+			}
+			if title != "" {
+				return title
+			}
+		case "h1", "h2":
+			title, err := collectText(d)
+			if err != nil {
+				return ""
+			}
+			if title != "" {
+				return title
+			}
+		case "script", "style":
+			if err := d.Skip(); err != nil {
+				return ""
+			}
+		}
+	}
+}
+
+// GetChapterText returns the plain text of a specific chapter
+//
+// This method returns the content of the chapter at the specified index with
+// all markup removed. HTML entities are decoded, whitespace is collapsed, and
+// paragraphs and other block elements are separated by blank lines. Scripts
+// and styles are dropped. The index is zero-based, as for GetChapterContent.
+//
+// Example:
+//
+//	text, err := e.GetChapterText(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(text)
+func (e *Epub) GetChapterText(chapterIndex int, opts ...Option) (string, error) {
+	content, err := e.GetChapterContent(chapterIndex, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := htmlToText([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to extract chapter text: %w", err)
+	}
+	return text, nil
+}
+
+// isCJK reports whether r belongs to a script written without spaces
+// between words, in which every character is counted as a word
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r)
+}
+
+// countWords counts the words in plain text
+//
+// Runs of letters, digits and marks separated by whitespace or punctuation
+// count as one word each. Han, Hiragana and Katakana characters are counted
+// individually since those scripts do not separate words with spaces.
+func countWords(text string) int {
+	words, chars := countWordsAndCJK(text)
+	return words + chars
+}
+
+// countWordsAndCJK counts the words of plain text as countWords does, but
+// returns the Han, Hiragana and Katakana characters separately from the
+// other words
+func countWordsAndCJK(text string) (words, chars int) {
+	inWord := false
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			chars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case r == '\'' || r == '’' || r == '-':
+			// Apostrophes and hyphens inside a word do not split it
+		default:
+			inWord = false
+		}
+	}
+
+	return words, chars
+}
+
+// ChapterWordCount returns the number of words in a specific chapter
+//
+// The chapter's markup is removed as for GetChapterText before counting.
+// Text in Chinese and Japanese scripts is counted per character. The index
+// is zero-based, as for GetChapterContent.
+func (e *Epub) ChapterWordCount(chapterIndex int) (int, error) {
+	text, err := e.GetChapterText(chapterIndex)
+	if err != nil {
+		return 0, err
+	}
+	return countWords(text), nil
+}
+
+// ChapterWordCounts returns the number of words in every spine item
+//
+// The returned slice has one entry per spine item, so that the count for
+// chapter index i is at position i. Spine items that are not HTML documents
+// have a count of zero.
+//
+// Example:
+//
+//	counts, err := e.ChapterWordCounts()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for i, n := range counts {
+//		fmt.Printf("Chapter %d: %d words\n", i+1, n)
+//	}
+func (e *Epub) ChapterWordCounts() ([]int, error) {
+	counts := make([]int, len(e.Spine))
+
+	for i, itemRef := range e.Spine {
+		item := e.findItemByID(itemRef.IDRef)
+		if item == nil || !strings.Contains(item.MediaType, "html") {
+			continue
+		}
+
+		n, err := e.ChapterWordCount(i)
+		if err != nil {
+			return nil, err
+		}
+		counts[i] = n
+	}
+
+	return counts, nil
+}
+
+// WordCount returns the total number of words in the book
+//
+// This method sums the word counts of all HTML documents in the spine. See
+// ChapterWordCount for how words are counted.
+func (e *Epub) WordCount() (int, error) {
+	counts, err := e.ChapterWordCounts()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total, nil
+}
+
+// Default reading speeds used by EstimatedReadingTime and ChapterReadingTime
+const (
+	defaultWordsPerMinute    = 200
+	defaultCJKCharsPerMinute = 500
+)
+
+// readingTime estimates how long plain text takes to read at the given
+// number of words per minute, reading CJK characters at
+// defaultCJKCharsPerMinute
+func readingTime(text string, wordsPerMinute int) time.Duration {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = defaultWordsPerMinute
+	}
+
+	words, chars := countWordsAndCJK(text)
+	minutes := float64(words)/float64(wordsPerMinute) + float64(chars)/defaultCJKCharsPerMinute
+	return time.Duration(minutes * float64(time.Minute)).Round(time.Second)
+}
+
+// ChapterReadingTime estimates the time needed to read a specific chapter
+//
+// Words are counted as for ChapterWordCount and read at wordsPerMinute, or
+// 200 words per minute if wordsPerMinute is zero or negative. Chinese and
+// Japanese characters are counted individually instead and read at 500
+// characters per minute. The index is zero-based, as for GetChapterContent.
+//
+// Example:
+//
+//	d, err := e.ChapterReadingTime(0, 250)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%.0f min\n", d.Minutes())
+func (e *Epub) ChapterReadingTime(chapterIndex, wordsPerMinute int) (time.Duration, error) {
+	text, err := e.GetChapterText(chapterIndex)
+	if err != nil {
+		return 0, err
+	}
+	return readingTime(text, wordsPerMinute), nil
+}
+
+// EstimatedReadingTime estimates the time needed to read the whole book
+//
+// This method sums the reading times of all HTML documents in the spine, as
+// computed by ChapterReadingTime.
+func (e *Epub) EstimatedReadingTime(wordsPerMinute int) (time.Duration, error) {
+	var total time.Duration
+
+	for i, itemRef := range e.Spine {
+		item := e.findItemByID(itemRef.IDRef)
+		if item == nil || !strings.Contains(item.MediaType, "html") {
+			continue
+		}
+
+		d, err := e.ChapterReadingTime(i, wordsPerMinute)
+		if err != nil {
+			return 0, err
+		}
+		total += d
+	}
+
+	return total, nil
+}
+
+// xmlNamespace is the namespace of the xml: attributes such as xml:lang
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// ChapterLanguage returns the language of a specific chapter
+//
+// The language is taken from the xml:lang or lang attribute of the chapter's
+// root element, preferring xml:lang, so that chapters of mixed-language
+// anthologies can be hyphenated and rendered with the right fonts. If the
+// root element declares no language, the book's primary language is
+// returned. The index is zero-based, as for GetChapterContent.
+//
+// Example:
+//
+//	lang, err := e.ChapterLanguage(3)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println("chapter 4 is written in", lang)
+func (e *Epub) ChapterLanguage(chapterIndex int) (string, error) {
+	item, err := e.chapterItem(chapterIndex)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := e.getFile(e.resolveHref(item.Href))
+	if err != nil {
+		return "", fmt.Errorf("failed to get chapter content: %w", err)
+	}
+
+	if lang := rootLanguage(content); lang != "" {
+		return lang, nil
+	}
+	return e.Metadata.Language, nil
+}
+
+// rootLanguage returns the xml:lang or lang attribute of the root element of
+// an (X)HTML document, or an empty string if it declares neither
+func rootLanguage(content []byte) string {
+	d := newHTMLDecoder(content)
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return ""
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var lang string
+		for _, attr := range start.Attr {
+			if !strings.EqualFold(attr.Name.Local, "lang") {
+				continue
+			}
+			value := strings.TrimSpace(attr.Value)
+			if attr.Name.Space == xmlNamespace && value != "" {
+				return value
+			}
+			if lang == "" {
+				lang = value
+			}
+		}
+		return lang
+	}
+}
@@ -0,0 +1,197 @@
+package epub
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLToText(t *testing.T) {
+	html := `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>Ignored</title><style>p { color: red; }</style></head>
+<body>
+	<h1>Heading</h1>
+	<p>First   paragraph with <em>inline</em>
+	   text &amp; an entity&nbsp;here.</p>
+	<script>alert("dropped");</script>
+	<div>Line one<br/>Line two</div>
+	<p>Caf&eacute; &lt;tag&gt;</p>
+</body>
+</html>`
+
+	text, err := htmlToText([]byte(html))
+	if err != nil {
+		t.Fatalf("Failed to extract text: %v", err)
+	}
+
+	want := "Heading\n\nFirst paragraph with inline text & an entity here.\n\nLine one\nLine two\n\nCafé <tag>"
+	if text != want {
+		t.Errorf("Unexpected text:\ngot:  %q\nwant: %q", text, want)
+	}
+}
+
+func TestEpub_GetChapterText(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	text, err := epub.GetChapterText(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter text: %v", err)
+	}
+
+	if !strings.HasPrefix(text, "第一章 百世书\n\n魔门，初圣宗。\n\n") {
+		t.Errorf("Unexpected chapter text prefix: %q", text[:min(len(text), 80)])
+	}
+
+	if strings.Contains(text, "<p>") || strings.Contains(text, "text-indent") {
+		t.Error("Expected markup and styles to be removed from chapter text")
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	tests := map[string]int{
+		"":                              0,
+		"Hello, world!":                 2,
+		"It's a well-known fact.":       4,
+		"魔门，初圣宗。":                       5,
+		"Chapter 1: 第一章":                5,
+		"  spaced\n\nout   words here ": 4,
+	}
+
+	for text, want := range tests {
+		if got := countWords(text); got != want {
+			t.Errorf("countWords(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestEpub_WordCount(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	counts, err := epub.ChapterWordCounts()
+	if err != nil {
+		t.Fatalf("Failed to count words: %v", err)
+	}
+
+	if len(counts) != len(epub.Spine) {
+		t.Fatalf("Expected %d counts, got %d", len(epub.Spine), len(counts))
+	}
+
+	chapterCount, err := epub.ChapterWordCount(1)
+	if err != nil {
+		t.Fatalf("Failed to count chapter words: %v", err)
+	}
+
+	if chapterCount == 0 || chapterCount != counts[1] {
+		t.Errorf("Expected chapter count %d to match breakdown %d", chapterCount, counts[1])
+	}
+
+	total, err := epub.WordCount()
+	if err != nil {
+		t.Fatalf("Failed to count book words: %v", err)
+	}
+
+	if total < chapterCount {
+		t.Errorf("Expected total %d to be at least the chapter count %d", total, chapterCount)
+	}
+}
+
+func TestExtractHTMLTitle(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{`<html><head><title>  Tom &amp; Jerry </title></head><body><h1>Heading</h1></body></html>`, "Tom & Jerry"},
+		{`<html><head><title></title></head><body><h2>Second &mdash; Level</h2><h1>Later</h1></body></html>`, "Second — Level"},
+		{`<html><body><p>Intro</p><h1>First <em>Heading</em></h1></body></html>`, "First Heading"},
+		{`<html><body><h3>Too deep</h3></body></html>`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractHTMLTitle([]byte(tt.html)); got != tt.want {
+			t.Errorf("extractHTMLTitle(%q) = %q, want %q", tt.html, got, tt.want)
+		}
+	}
+}
+
+func TestEpub_ChapterLanguage(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" lang="de" xml:lang="fr"><body><p lang="it">Texte</p></body></html>`
+	files["OEBPS/text/two.xhtml"] = testChapterXHTML
+	epub := newTestEpub(t, files)
+
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "fr"},
+		{1, "en"},
+	}
+	for _, tt := range tests {
+		lang, err := epub.ChapterLanguage(tt.index)
+		if err != nil {
+			t.Fatalf("Failed to get language of chapter %d: %v", tt.index, err)
+		}
+		if lang != tt.want {
+			t.Errorf("Expected chapter %d to be in %q, got %q", tt.index, tt.want, lang)
+		}
+	}
+
+	if got := rootLanguage([]byte(`<html lang="ja"><body/></html>`)); got != "ja" {
+		t.Errorf("Expected lang attribute to be used, got %q", got)
+	}
+
+	if _, err := epub.ChapterLanguage(99); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
+
+func TestEpub_ReadingTime(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/text/one.xhtml"] = "<html><body><p>" + strings.Repeat("word ", 400) + "</p></body></html>"
+	files["OEBPS/text/two.xhtml"] = "<html><body><p>" + strings.Repeat("中文", 500) + "</p></body></html>"
+	epub := newTestEpub(t, files)
+
+	tests := []struct {
+		index int
+		wpm   int
+		want  time.Duration
+	}{
+		{0, 0, 2 * time.Minute},
+		{0, 100, 4 * time.Minute},
+		{1, 0, 2 * time.Minute},
+		{1, 100, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		d, err := epub.ChapterReadingTime(tt.index, tt.wpm)
+		if err != nil {
+			t.Fatalf("Failed to estimate reading time of chapter %d: %v", tt.index, err)
+		}
+		if d != tt.want {
+			t.Errorf("Expected chapter %d at %d wpm to take %v, got %v", tt.index, tt.wpm, tt.want, d)
+		}
+	}
+
+	total, err := epub.EstimatedReadingTime(-1)
+	if err != nil {
+		t.Fatalf("Failed to estimate reading time: %v", err)
+	}
+	if total != 4*time.Minute {
+		t.Errorf("Expected the book to take 4m0s, got %v", total)
+	}
+
+	if _, err := epub.ChapterReadingTime(99, 0); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+package epub
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TOCEntry represents an entry in the table of contents
+//
+// Href is the path of the target document relative to the directory of the
+// package document, like the hrefs of manifest items, regardless of where
+// the NCX or navigation document is located. Fragment holds the part of the
+// link after "#", if any. Level is the nesting depth of the entry, starting
+// at 1 for top-level entries.
+type TOCEntry struct {
+	Title    string
+	Href     string
+	Fragment string
+	Level    int
+	Children []TOCEntry
+}
+
+// TOCSource identifies the document a table of contents is parsed from
+type TOCSource int
+
+const (
+	// TOCSourceAuto prefers the EPUB 3 navigation document and falls back
+	// to the NCX
+	TOCSourceAuto TOCSource = iota
+
+	// TOCSourceNav is the EPUB 3 navigation document
+	TOCSourceNav
+
+	// TOCSourceNCX is the EPUB 2 NCX file
+	TOCSourceNCX
+)
+
+// String returns the name of the TOC source
+func (s TOCSource) String() string {
+	switch s {
+	case TOCSourceNav:
+		return "nav"
+	case TOCSourceNCX:
+		return "ncx"
+	default:
+		return "auto"
+	}
+}
+
+// GetTOC returns the table of contents as a tree of entries
+//
+// This method converts the parsed NCX or EPUB 3 navigation document into a
+// uniform tree of TOCEntry values. Sibling entries are ordered by their NCX
+// playOrder when every sibling declares one, and by document order otherwise.
+// If the book has no table of contents, an error is returned.
+//
+// Example:
+//
+//	toc, err := e.GetTOC()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, entry := range toc {
+//		fmt.Printf("%s -> %s\n", entry.Title, entry.Href)
+//	}
+func (e *Epub) GetTOC() ([]TOCEntry, error) {
+	if e.TOC == nil {
+		return nil, ErrNoTOC
+	}
+
+	return e.tocEntries(e.TOC.NavMap, 1), nil
+}
+
+// WalkTOC calls fn for every entry of the table of contents
+//
+// Entries are visited depth-first in the order returned by GetTOC, each
+// parent before its children. depth is zero for top-level entries, one less
+// than the entry's Level. If fn returns an error, the walk stops and the
+// error is returned. ErrNoTOC is returned if the book has no table of
+// contents.
+//
+// Example:
+//
+//	err := e.WalkTOC(func(entry epub.TOCEntry, depth int) error {
+//		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), entry.Title)
+//		return nil
+//	})
+func (e *Epub) WalkTOC(fn func(entry TOCEntry, depth int) error) error {
+	toc, err := e.GetTOC()
+	if err != nil {
+		return err
+	}
+	return walkTOCEntries(toc, 0, fn)
+}
+
+// GetTOCSource returns the document the table of contents was parsed from
+//
+// The result is TOCSourceNav or TOCSourceNCX, or TOCSourceAuto if the book
+// has no table of contents.
+//
+// Example:
+//
+//	if e.GetTOCSource() == epub.TOCSourceNCX {
+//		fmt.Println("table of contents taken from the NCX")
+//	}
+func (e *Epub) GetTOCSource() TOCSource {
+	if e.TOC == nil {
+		return TOCSourceAuto
+	}
+	return e.usedTOCSource
+}
+
+// TOCDepth returns the maximum nesting level of the table of contents
+//
+// The depth is computed from the parsed tree rather than taken from the
+// dtb:depth meta of the NCX, which is often wrong. A flat table of contents
+// has depth 1. Zero is returned if the book has no table of contents.
+//
+// Example:
+//
+//	levels := e.TOCDepth()
+//	fmt.Printf("The table of contents has %d levels\n", levels)
+func (e *Epub) TOCDepth() int {
+	if e.TOC == nil {
+		return 0
+	}
+	return navPointDepth(e.TOC.NavMap)
+}
+
+// navPointDepth returns the maximum nesting level of a list of NavPoints
+func navPointDepth(points []NavPoint) int {
+	depth := 0
+	for _, point := range points {
+		if d := 1 + navPointDepth(point.NavPoints); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// walkTOCEntries visits a list of sibling TOC entries and their descendants
+func walkTOCEntries(entries []TOCEntry, depth int, fn func(entry TOCEntry, depth int) error) error {
+	for _, entry := range entries {
+		if err := fn(entry, depth); err != nil {
+			return err
+		}
+		if err := walkTOCEntries(entry.Children, depth+1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tocEntries converts a list of sibling NavPoints into TOCEntries
+func (e *Epub) tocEntries(points []NavPoint, level int) []TOCEntry {
+	if len(points) == 0 {
+		return nil
+	}
+
+	sorted := make([]NavPoint, len(points))
+	copy(sorted, points)
+	if hasPlayOrder(sorted) {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, _ := strconv.Atoi(strings.TrimSpace(sorted[i].PlayOrder))
+			b, _ := strconv.Atoi(strings.TrimSpace(sorted[j].PlayOrder))
+			return a < b
+		})
+	}
+
+	entries := make([]TOCEntry, 0, len(sorted))
+	for _, point := range sorted {
+		href, fragment := e.resolveTOCHref(point.Src)
+		entries = append(entries, TOCEntry{
+			Title:    point.Label(),
+			Href:     href,
+			Fragment: fragment,
+			Level:    level,
+			Children: e.tocEntries(point.NavPoints, level+1),
+		})
+	}
+
+	return entries
+}
+
+// hasPlayOrder reports whether every NavPoint declares a numeric playOrder
+func hasPlayOrder(points []NavPoint) bool {
+	for _, point := range points {
+		if _, err := strconv.Atoi(strings.TrimSpace(point.PlayOrder)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveTOCHref splits a TOC link into its path, made relative to the
+// package document directory, and its fragment
+func (e *Epub) resolveTOCHref(src string) (string, string) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return "", ""
+	}
+
+	var fragment string
+	if i := strings.Index(src, "#"); i >= 0 {
+		src, fragment = src[:i], src[i+1:]
+	}
+	if src == "" || isExternalRef(src) {
+		return src, fragment
+	}
+
+	return e.packageRelative(resolveRef(e.tocPath, src)), fragment
+}
+
+// packageRelative converts a zip path into a path relative to the directory
+// of the package document
+func (e *Epub) packageRelative(zipPath string) string {
+	dir := e.RootFileDir()
+	if dir == "." {
+		return zipPath
+	}
+	if strings.HasPrefix(zipPath, dir+"/") {
+		return strings.TrimPrefix(zipPath, dir+"/")
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(zipPath))
+	if err != nil {
+		return zipPath
+	}
+	return filepath.ToSlash(rel)
+}
+
+// GetPageList returns the page list mapping print page numbers to locations
+//
+// The page list is taken from the pageList of the NCX or the page-list nav
+// of the EPUB 3 navigation document, whichever the table of contents was
+// parsed from. The Src of each PageTarget is relative to that document. If
+// the book has no page list, nil is returned.
+//
+// Example:
+//
+//	pages := e.GetPageList()
+//	if len(pages) > 0 {
+//		fmt.Printf("Page %s of %d\n", pages[41].Value, len(pages))
+//	}
+func (e *Epub) GetPageList() []PageTarget {
+	if e.TOC == nil {
+		return nil
+	}
+	return e.TOC.PageList
+}
+
+// SpineIndexForHref returns the spine index of the document a href points to
+//
+// The href is interpreted relative to the package document directory, like
+// manifest hrefs and TOCEntry.Href. Any "#fragment" is ignored, so several
+// TOC entries pointing into the same file map to the same index. An error is
+// returned if the href does not match a manifest item, or if the item is not
+// part of the spine.
+//
+// Example:
+//
+//	index, err := e.SpineIndexForHref("text/chapter2.xhtml#section1")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	content, err := e.GetChapterContent(index)
+func (e *Epub) SpineIndexForHref(href string) (int, error) {
+	file := href
+	if i := strings.Index(file, "#"); i >= 0 {
+		file = file[:i]
+	}
+
+	item := e.findItemByHref(file)
+	if item == nil {
+		return -1, fmt.Errorf("no manifest item found for href: %s", href)
+	}
+
+	for i, itemRef := range e.Spine {
+		if itemRef.IDRef == item.ID {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("manifest item %q is not in the spine: %s", item.ID, href)
+}
+
+// TOCEntryToSpineIndex returns the spine index of the document a TOC entry
+// points to
+//
+// This is a convenience wrapper around SpineIndexForHref.
+func (e *Epub) TOCEntryToSpineIndex(entry TOCEntry) (int, error) {
+	return e.SpineIndexForHref(entry.Href)
+}
+
+// tocTitles maps the package-relative path of every document referenced by
+// the table of contents to the label of the first entry pointing into it
+func (e *Epub) tocTitles() map[string]string {
+	titles := make(map[string]string)
+
+	toc, err := e.GetTOC()
+	if err != nil {
+		return titles
+	}
+
+	var walk func(entries []TOCEntry)
+	walk = func(entries []TOCEntry) {
+		for _, entry := range entries {
+			if entry.Href != "" && entry.Title != "" {
+				href := unescapeHref(entry.Href)
+				if _, ok := titles[href]; !ok {
+					titles[href] = entry.Title
+				}
+			}
+			walk(entry.Children)
+		}
+	}
+	walk(toc)
+
+	return titles
+}
+
+// chapterTitle resolves the title of the chapter at the given spine index
+//
+// The label of the TOC entry pointing at the chapter's document is preferred,
+// followed by the title found in the document itself and finally a generic
+// "Chapter N".
+func (e *Epub) chapterTitle(chapterIndex int, item *Item, content []byte, titles map[string]string) string {
+	if title, ok := titles[unescapeHref(path.Clean(filepath.ToSlash(item.Href)))]; ok {
+		return title
+	}
+
+	if title := extractHTMLTitle(content); title != "" {
+		return title
+	}
+
+	return fmt.Sprintf("Chapter %d", chapterIndex+1)
+}
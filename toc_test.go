@@ -0,0 +1,417 @@
+package epub
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEpub_GetTOC(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	toc, err := epub.GetTOC()
+	if err != nil {
+		t.Fatalf("Failed to get TOC: %v", err)
+	}
+
+	if len(toc) != 2 {
+		t.Fatalf("Expected 2 top-level entries, got %d", len(toc))
+	}
+
+	first := toc[0]
+	if first.Title != "Part One" || first.Href != "text/one.xhtml" || first.Level != 1 {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+
+	if len(first.Children) != 2 {
+		t.Fatalf("Expected 2 children, got %d", len(first.Children))
+	}
+
+	section := first.Children[0]
+	if section.Href != "text/one.xhtml" || section.Fragment != "s1" || section.Level != 2 {
+		t.Errorf("Unexpected section entry: %+v", section)
+	}
+
+	deep := first.Children[1].Children[0]
+	if deep.Title != "Deep" || deep.Level != 3 {
+		t.Errorf("Unexpected deep entry: %+v", deep)
+	}
+}
+
+func TestEpub_GetTOC_NCX(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/toc.ncx"] = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+	<navMap>
+		<navPoint id="n2" playOrder="2"><navLabel><text>Second</text></navLabel><content src="text/two.xhtml"/></navPoint>
+		<navPoint id="n1" playOrder="1"><navLabel><text>First</text></navLabel><content src="text/one.xhtml#top"/></navPoint>
+	</navMap>
+</ncx>`
+	delete(files, "OEBPS/nav.xhtml")
+	files["OEBPS/content.opf"] = testNavOPF
+	epub := newTestEpub(t, files)
+
+	toc, err := epub.GetTOC()
+	if err != nil {
+		t.Fatalf("Failed to get TOC: %v", err)
+	}
+
+	if len(toc) != 2 || toc[0].Title != "First" || toc[1].Title != "Second" {
+		t.Fatalf("Expected entries ordered by playOrder, got %+v", toc)
+	}
+
+	if toc[0].Href != "text/one.xhtml" || toc[0].Fragment != "top" {
+		t.Errorf("Unexpected href split: %q # %q", toc[0].Href, toc[0].Fragment)
+	}
+}
+
+func TestEpub_GetTOC_Fixture(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	toc, err := epub.GetTOC()
+	if err != nil {
+		t.Fatalf("Failed to get TOC: %v", err)
+	}
+
+	if len(toc) < 2 || toc[1].Href != "001.html" || toc[1].Title != "第一章 百世书" {
+		t.Errorf("Unexpected second TOC entry: %+v", toc[1])
+	}
+}
+
+func TestEpub_SpineIndexForHref(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	toc, err := epub.GetTOC()
+	if err != nil {
+		t.Fatalf("Failed to get TOC: %v", err)
+	}
+
+	index, err := epub.TOCEntryToSpineIndex(toc[1])
+	if err != nil {
+		t.Fatalf("Failed to map TOC entry: %v", err)
+	}
+	if index != 1 {
+		t.Errorf("Expected spine index 1 for %q, got %d", toc[1].Href, index)
+	}
+
+	index, err = epub.SpineIndexForHref("text/one.xhtml#s1")
+	if err != nil {
+		t.Fatalf("Failed to map href with fragment: %v", err)
+	}
+	if index != 0 {
+		t.Errorf("Expected spine index 0, got %d", index)
+	}
+
+	if _, err := epub.SpineIndexForHref("nav.xhtml"); err == nil {
+		t.Error("Expected error for manifest item not in the spine, got nil")
+	}
+
+	if _, err := epub.SpineIndexForHref("missing.xhtml"); err == nil {
+		t.Error("Expected error for href not in the manifest, got nil")
+	}
+}
+
+func TestEpub_GetChapters_TitlesFromTOCHrefs(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/nav.xhtml"] = `<html xmlns:epub="http://www.idpf.org/2007/ops"><body>
+		<nav epub:type="toc"><ol>
+			<li><a href="text/two.xhtml#start">Second Part</a></li>
+		</ol></nav></body></html>`
+	files["OEBPS/text/one.xhtml"] = `<html><head><title>Opening</title></head><body><p>One</p></body></html>`
+	files["OEBPS/text/two.xhtml"] = `<html><head><title>Ignored</title></head><body><p>Two</p></body></html>`
+	epub := newTestEpub(t, files)
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	if chapters[0].Title != "Opening" {
+		t.Errorf("Expected title from <title> element, got %q", chapters[0].Title)
+	}
+
+	if chapters[1].Title != "Second Part" {
+		t.Errorf("Expected title from TOC href match, got %q", chapters[1].Title)
+	}
+
+	files["OEBPS/text/one.xhtml"] = `<html><body><h1>Prologue</h1><p>One</p></body></html>`
+	epub = newTestEpub(t, files)
+
+	chapters, err = epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	if chapters[0].Title != "Prologue" {
+		t.Errorf("Expected title from first heading, got %q", chapters[0].Title)
+	}
+
+	files["OEBPS/text/one.xhtml"] = `<html><body><p>One</p></body></html>`
+	epub = newTestEpub(t, files)
+
+	chapters, err = epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+
+	if chapters[0].Title != "Chapter 1" {
+		t.Errorf("Expected generic title, got %q", chapters[0].Title)
+	}
+}
+
+func TestEpub_GetChapter(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	chapter, err := epub.GetChapter(1)
+	if err != nil {
+		t.Fatalf("Failed to get chapter: %v", err)
+	}
+
+	want := Chapter{Title: "Part Two", Content: testChapterXHTML, Order: 2, Href: "text/two.xhtml", ID: "c2"}
+	if chapter != want {
+		t.Errorf("Unexpected chapter: got %+v, want %+v", chapter, want)
+	}
+
+	chapters, err := epub.GetChapters()
+	if err != nil {
+		t.Fatalf("Failed to get chapters: %v", err)
+	}
+	if chapters[1] != chapter {
+		t.Errorf("Expected GetChapter to match GetChapters, got %+v and %+v", chapter, chapters[1])
+	}
+
+	if _, err := epub.GetChapter(5); !errors.Is(err, ErrChapterOutOfRange) {
+		t.Errorf("Expected ErrChapterOutOfRange, got %v", err)
+	}
+	if _, err := epub.GetChapter(0, WithMaxContentLength(10)); err == nil {
+		t.Error("Expected error for chapter exceeding the maximum length, got nil")
+	}
+}
+
+func TestEpub_GetChapterByHref(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	chapter, err := epub.GetChapterByHref("text/two.xhtml#intro")
+	if err != nil {
+		t.Fatalf("Failed to get chapter by href: %v", err)
+	}
+
+	if chapter.Order != 2 || chapter.Title != "Part Two" || chapter.Content != testChapterXHTML {
+		t.Errorf("Unexpected chapter: order %d, title %q", chapter.Order, chapter.Title)
+	}
+
+	if _, err := epub.GetChapterByHref("toc.ncx"); err == nil {
+		t.Error("Expected error for href not in the spine, got nil")
+	}
+}
+
+func TestEpub_NextChapter(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.NewReplacer(
+		`<item id="c2"`, `<item id="notes" href="text/notes.xhtml" media-type="application/xhtml+xml"/>
+		<item id="img" href="images/a.png" media-type="image/png"/>
+		<item id="c2"`,
+		`<itemref idref="c2"/>`, `<itemref idref="notes" linear="no"/><itemref idref="img"/><itemref idref="c2"/>`,
+	).Replace(testNavOPF)
+	files["OEBPS/text/notes.xhtml"] = testChapterXHTML
+	files["OEBPS/images/a.png"] = "png"
+	epub := newTestEpub(t, files)
+
+	next, ok, err := epub.NextChapter("text/one.xhtml#s1")
+	if err != nil || !ok {
+		t.Fatalf("Expected a next chapter, got ok=%v, err=%v", ok, err)
+	}
+	if next.Order != 4 || next.Title != "Part Two" {
+		t.Errorf("Expected the next linear chapter to be Part Two at order 4, got %q at %d", next.Title, next.Order)
+	}
+
+	prev, ok, err := epub.PrevChapter("text/two.xhtml")
+	if err != nil || !ok {
+		t.Fatalf("Expected a previous chapter, got ok=%v, err=%v", ok, err)
+	}
+	if prev.Order != 1 || prev.Title != "Part One" {
+		t.Errorf("Expected the previous chapter to be Part One at order 1, got %q at %d", prev.Title, prev.Order)
+	}
+
+	// Non-linear documents still have neighbors
+	if next, ok, err := epub.NextChapter("text/notes.xhtml"); err != nil || !ok || next.Order != 4 {
+		t.Errorf("Expected Part Two after the notes, got %+v, ok=%v, err=%v", next, ok, err)
+	}
+
+	if _, ok, err := epub.NextChapter("text/two.xhtml"); err != nil || ok {
+		t.Errorf("Expected no chapter after the last one, got ok=%v, err=%v", ok, err)
+	}
+	if _, ok, err := epub.PrevChapter("text/one.xhtml"); err != nil || ok {
+		t.Errorf("Expected no chapter before the first one, got ok=%v, err=%v", ok, err)
+	}
+
+	if _, _, err := epub.NextChapter("missing.xhtml"); err == nil {
+		t.Error("Expected error for href not in the manifest, got nil")
+	}
+}
+
+func TestEpub_GetPageList(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/toc.ncx"] = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+	<navMap>
+		<navPoint id="n1" playOrder="1"><navLabel><text>One</text></navLabel><content src="text/one.xhtml"/></navPoint>
+	</navMap>
+	<pageList>
+		<pageTarget id="p1" value="1" type="normal" playOrder="2"><navLabel><text>1</text></navLabel><content src="text/one.xhtml#page1"/></pageTarget>
+		<pageTarget id="pii" type="front" playOrder="3"><navLabel><text>ii</text></navLabel><content src="text/two.xhtml#pii"/></pageTarget>
+	</pageList>
+</ncx>`
+	delete(files, "OEBPS/nav.xhtml")
+	epub := newTestEpub(t, files)
+
+	want := []PageTarget{
+		{ID: "p1", Value: "1", Type: "normal", PlayOrder: "2", Label: "1", Src: "text/one.xhtml#page1"},
+		{ID: "pii", Type: "front", PlayOrder: "3", Label: "ii", Src: "text/two.xhtml#pii"},
+	}
+	if got := epub.GetPageList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected NCX page list:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestEpub_GetPageList_Nav(t *testing.T) {
+	files := testEPUB3Files()
+	files["OEBPS/nav.xhtml"] = strings.Replace(testNavXHTML, `<nav epub:type="landmarks">`, `<nav epub:type="page-list" hidden="">
+		<ol>
+			<li><a href="text/one.xhtml#p1">1</a></li>
+			<li><a href="text/two.xhtml#p2">2</a></li>
+		</ol>
+	</nav>
+	<nav epub:type="landmarks">`, 1)
+	epub := newTestEpub(t, files)
+
+	want := []PageTarget{
+		{Value: "1", Label: "1", Src: "text/one.xhtml#p1"},
+		{Value: "2", Label: "2", Src: "text/two.xhtml#p2"},
+	}
+	if got := epub.GetPageList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected nav page list:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	if pages := newTestEpub(t, testEPUB3Files()).GetPageList(); pages != nil {
+		t.Errorf("Expected no page list, got %+v", pages)
+	}
+}
+
+func TestEpub_WalkTOC(t *testing.T) {
+	epub := newTestEpub(t, testEPUB3Files())
+
+	var visited []string
+	err := epub.WalkTOC(func(entry TOCEntry, depth int) error {
+		visited = append(visited, strings.Repeat("-", depth)+entry.Title)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk TOC: %v", err)
+	}
+
+	want := []string{"Part One", "-Section 1.1", "-Unlinked", "--Deep", "Part Two"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("Expected visit order %v, got %v", want, visited)
+	}
+
+	stop := errors.New("stop")
+	visited = nil
+	err = epub.WalkTOC(func(entry TOCEntry, depth int) error {
+		visited = append(visited, entry.Title)
+		if entry.Title == "Section 1.1" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if len(visited) != 2 {
+		t.Errorf("Expected the walk to stop after 2 entries, got %v", visited)
+	}
+
+	epub.TOC = nil
+	if err := epub.WalkTOC(func(TOCEntry, int) error { return nil }); !errors.Is(err, ErrNoTOC) {
+		t.Errorf("Expected ErrNoTOC, got %v", err)
+	}
+}
+
+func TestEpub_TOCDepth(t *testing.T) {
+	if depth := newTestEpub(t, testEPUB3Files()).TOCDepth(); depth != 3 {
+		t.Errorf("Expected depth 3, got %d", depth)
+	}
+
+	files := testEPUB3Files()
+	files["OEBPS/toc.ncx"] = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+	<head><meta name="dtb:depth" content="4"/></head>
+	<navMap>
+		<navPoint id="n1" playOrder="1"><navLabel><text>First</text></navLabel><content src="text/one.xhtml"/></navPoint>
+		<navPoint id="n2" playOrder="2"><navLabel><text>Second</text></navLabel><content src="text/two.xhtml"/></navPoint>
+	</navMap>
+</ncx>`
+	delete(files, "OEBPS/nav.xhtml")
+	files["OEBPS/content.opf"] = testNavOPF
+	if depth := newTestEpub(t, files).TOCDepth(); depth != 1 {
+		t.Errorf("Expected the depth of a flat NCX to be 1 regardless of dtb:depth, got %d", depth)
+	}
+
+	epub := newTestEpub(t, testEPUB3Files())
+	epub.TOC = nil
+	if depth := epub.TOCDepth(); depth != 0 {
+		t.Errorf("Expected depth 0 without a TOC, got %d", depth)
+	}
+}
+
+func TestWithTOCSource(t *testing.T) {
+	data := buildTestZip(t, testEPUB3Files())
+
+	tests := []struct {
+		src       TOCSource
+		wantUsed  TOCSource
+		wantLabel string
+	}{
+		{TOCSourceAuto, TOCSourceNav, "Part One"},
+		{TOCSourceNav, TOCSourceNav, "Part One"},
+		{TOCSourceNCX, TOCSourceNCX, "NCX One"},
+	}
+	for _, tt := range tests {
+		epub, err := OpenBytes(data, WithTOCSource(tt.src))
+		if err != nil {
+			t.Fatalf("Failed to open EPUB with TOC source %v: %v", tt.src, err)
+		}
+		if used := epub.GetTOCSource(); used != tt.wantUsed {
+			t.Errorf("Expected TOC source %v for %v, got %v", tt.wantUsed, tt.src, used)
+		}
+		if label := epub.TOC.NavMap[0].Label(); label != tt.wantLabel {
+			t.Errorf("Expected first label %q for %v, got %q", tt.wantLabel, tt.src, label)
+		}
+	}
+
+	// Without a navigation document, only Nav leaves the book without a TOC
+	files := testEPUB3Files()
+	files["OEBPS/content.opf"] = strings.Replace(testNavOPF, ` properties="nav"`, "", 1)
+	data = buildTestZip(t, files)
+
+	epub, err := OpenBytes(data, WithTOCSource(TOCSourceNav))
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	if epub.TOC != nil || epub.GetTOCSource() != TOCSourceAuto {
+		t.Errorf("Expected no TOC, got %v from %v", epub.TOC, epub.GetTOCSource())
+	}
+
+	epub, err = OpenBytes(data)
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	if epub.GetTOCSource() != TOCSourceNCX {
+		t.Errorf("Expected the NCX fallback, got %v", epub.GetTOCSource())
+	}
+}
@@ -0,0 +1,153 @@
+package epub
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// epubMimetype is the required content of the mimetype file
+const epubMimetype = "application/epub+zip"
+
+// Validate checks the structural integrity of the EPUB
+//
+// This method reports the common structural problems that make EPUBs fail to
+// open in reading systems:
+//
+//   - a missing mimetype file, a mimetype file with the wrong content, or one
+//     that is not the first, uncompressed entry of the archive
+//   - a container.xml rootfile that does not exist in the archive
+//   - manifest items whose files do not exist in the archive
+//   - spine itemrefs without a matching manifest item
+//   - table of contents entries pointing at missing files
+//
+// All problems found are returned; an empty result means none of these checks
+// failed. This is not a full replacement for EPUBCheck.
+//
+// Example:
+//
+//	for _, problem := range e.Validate() {
+//		fmt.Println("warning:", problem)
+//	}
+func (e *Epub) Validate() []error {
+	var problems []error
+
+	problems = append(problems, e.mimetypeErrors()...)
+
+	if e.RootFile == "" {
+		problems = append(problems, fmt.Errorf("container.xml does not declare a rootfile"))
+	} else if _, ok := e.findFile(e.RootFile); !ok {
+		problems = append(problems, fmt.Errorf("rootfile %s does not exist", e.RootFile))
+	}
+
+	for _, item := range e.Manifest {
+		if isExternalRef(item.Href) {
+			continue
+		}
+		itemPath := e.resolveHref(item.Href)
+		if _, ok := e.findFile(itemPath); !ok {
+			problems = append(problems, fmt.Errorf("manifest item %q refers to missing file %s", item.ID, itemPath))
+		}
+	}
+
+	for i, itemRef := range e.Spine {
+		if e.findItemByID(itemRef.IDRef) == nil {
+			problems = append(problems, fmt.Errorf("spine item %d refers to unknown manifest item %q", i, itemRef.IDRef))
+		}
+	}
+
+	problems = append(problems, e.tocErrors()...)
+
+	return problems
+}
+
+// CheckMimetype verifies the mimetype file rules of the OCF container
+//
+// The EPUB specification requires the first entry of the archive to be an
+// uncompressed file named "mimetype" containing exactly
+// "application/epub+zip". Many reading systems refuse books that break this
+// rule even though this package can parse them. If the rule is violated, the
+// returned error describes every problem found.
+func (e *Epub) CheckMimetype() error {
+	return errors.Join(e.mimetypeErrors()...)
+}
+
+// mimetypeErrors checks the mimetype file rules of the OCF container
+func (e *Epub) mimetypeErrors() []error {
+	archive, ok := e.src.(archiveSource)
+	if !ok {
+		// Unpacked EPUBs have no entry order or compression to check
+		return e.mimetypeContentErrors()
+	}
+
+	names := e.src.list()
+	if len(names) == 0 || names[0] != "mimetype" {
+		if _, ok := e.findFile("mimetype"); !ok {
+			return []error{fmt.Errorf("mimetype file is missing")}
+		}
+		return []error{fmt.Errorf("mimetype file is not the first entry in the archive")}
+	}
+
+	var problems []error
+	if method, _ := archive.method("mimetype"); method != zip.Store {
+		problems = append(problems, fmt.Errorf("mimetype file is compressed"))
+	}
+
+	return append(problems, e.mimetypeContentErrors()...)
+}
+
+// mimetypeContentErrors checks that the mimetype file contains exactly the
+// EPUB media type
+func (e *Epub) mimetypeContentErrors() []error {
+	name, ok := e.findFile("mimetype")
+	if !ok {
+		return []error{fmt.Errorf("mimetype file is missing")}
+	}
+
+	rc, err := e.src.open(name)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read mimetype file: %w", err)}
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, int64(len(epubMimetype))+1))
+	if err != nil {
+		return []error{fmt.Errorf("failed to read mimetype file: %w", err)}
+	}
+	if string(content) != epubMimetype {
+		return []error{fmt.Errorf("mimetype file contains %q, want %q", content, epubMimetype)}
+	}
+
+	return nil
+}
+
+// tocErrors reports table of contents entries pointing at missing files
+func (e *Epub) tocErrors() []error {
+	toc, err := e.GetTOC()
+	if err != nil {
+		return nil
+	}
+
+	var problems []error
+	checked := make(map[string]bool)
+
+	var walk func(entries []TOCEntry)
+	walk = func(entries []TOCEntry) {
+		for _, entry := range entries {
+			walk(entry.Children)
+
+			if entry.Href == "" || checked[entry.Href] || isExternalRef(entry.Href) {
+				continue
+			}
+			checked[entry.Href] = true
+
+			if _, ok := e.findFile(e.resolveHref(entry.Href)); !ok {
+				problems = append(problems, fmt.Errorf("table of contents entry %q refers to missing file %s", entry.Title, entry.Href))
+			}
+		}
+	}
+	walk(toc)
+
+	return problems
+}
@@ -0,0 +1,123 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEpub_Validate(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if problems := epub.Validate(); len(problems) != 0 {
+		t.Errorf("Expected valid fixture, got %v", problems)
+	}
+}
+
+func TestEpub_Validate_Problems(t *testing.T) {
+	files := testEPUB3Files()
+	opf := strings.Replace(testNavOPF, "<manifest>", `<manifest>
+		<item id="missing" href="images/missing.png" media-type="image/png"/>`, 1)
+	files["OEBPS/content.opf"] = strings.Replace(opf, "<spine toc=\"ncx\">", `<spine toc="ncx"><itemref idref="ghost"/>`, 1)
+	files["OEBPS/nav.xhtml"] = strings.Replace(testNavXHTML, "text/two.xhtml", "text/gone.xhtml", 1)
+	epub := newTestEpub(t, files)
+
+	problems := epub.Validate()
+
+	for _, want := range []string{"images/missing.png", `"ghost"`, "text/gone.xhtml"} {
+		found := false
+		for _, problem := range problems {
+			if strings.Contains(problem.Error(), want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a problem mentioning %s, got %v", want, problems)
+		}
+	}
+}
+
+func TestEpub_Validate_Mimetype(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"META-INF/container.xml": testContainerXML,
+		"OEBPS/content.opf":      testNavOPF,
+		"OEBPS/toc.ncx":          testNCX,
+	} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		io.WriteString(fw, content)
+	}
+	fw, err := w.Create("mimetype")
+	if err != nil {
+		t.Fatalf("Failed to create mimetype entry: %v", err)
+	}
+	io.WriteString(fw, "application/zip")
+	w.Close()
+
+	epub, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse EPUB: %v", err)
+	}
+
+	if err := epub.CheckMimetype(); err == nil || !strings.Contains(err.Error(), "first entry") {
+		t.Errorf("Expected mimetype ordering problem, got %v", err)
+	}
+}
+
+func TestEpub_CheckMimetype(t *testing.T) {
+	epub, err := Open(getTestEpubPath())
+	if err != nil {
+		t.Fatalf("Failed to open EPUB: %v", err)
+	}
+	defer epub.Close()
+
+	if err := epub.CheckMimetype(); err != nil {
+		t.Errorf("Expected fixture mimetype to be valid, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	fw, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Deflate})
+	if err != nil {
+		t.Fatalf("Failed to create mimetype entry: %v", err)
+	}
+	io.WriteString(fw, "application/epub+zip\n")
+	for name, content := range map[string]string{
+		"META-INF/container.xml": testContainerXML,
+		"OEBPS/content.opf":      testNavOPF,
+		"OEBPS/toc.ncx":          testNCX,
+	} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		io.WriteString(fw, content)
+	}
+	w.Close()
+
+	epub, err = OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse EPUB: %v", err)
+	}
+
+	err = epub.CheckMimetype()
+	if err == nil {
+		t.Fatal("Expected compressed mimetype with trailing newline to be rejected")
+	}
+
+	for _, want := range []string{"compressed", "contains"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
+}